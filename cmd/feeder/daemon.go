@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/daemon"
+	alliance_provider "github.com/terra-money/oracle-feeder-go/internal/provider/alliance"
+	"github.com/terra-money/oracle-feeder-go/internal/types"
+)
+
+// runDaemon runs the jobs of DefaultFeederConfig until the process is
+// interrupted, the jobs signing with the same key never running at once.
+func runDaemon() error {
+	jobs, err := daemonJobs(config.DefaultFeederConfig)
+	if err != nil {
+		return err
+	}
+	d, err := daemon.New(jobs)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	log.Printf("Running %d feeder jobs", len(jobs))
+	d.Run(ctx)
+	log.Print("Feeder daemon stopped")
+	return nil
+}
+
+func daemonJobs(cfg config.FeederConfig) ([]daemon.Job, error) {
+	var jobs []daemon.Job
+	for name, jobConfig := range cfg.Jobs {
+		feederType, err := types.ParseFeederTypeFromString(name)
+		if err != nil {
+			return nil, err
+		}
+		schedule, err := jobSchedule(jobConfig)
+		if err != nil {
+			return nil, fmt.Errorf("job %s: %w", name, err)
+		}
+		alliancesQuerierProvider := alliance_provider.NewAlliancesQuerierProvider(feederType)
+		jobs = append(jobs, daemon.Job{
+			Name:     name,
+			Schedule: schedule,
+			Jitter:   time.Duration(jobConfig.Jitter) * time.Second,
+			Retry: daemon.RetryPolicy{
+				Retries:  jobConfig.Retries,
+				Delay:    time.Duration(jobConfig.RetryDelay) * time.Second,
+				MaxDelay: time.Duration(jobConfig.MaxRetryDelay) * time.Second,
+			},
+			// the account sequence is shared by the transactions of the key,
+			// a run holds the lock until its transaction is included in a block
+			Lock: alliancesQuerierProvider.Signer(),
+			Run: func(ctx context.Context) error {
+				txHash, err := alliancesQuerierProvider.SubmitTx(ctx)
+				if err != nil {
+					if !retryable(err) {
+						return daemon.Permanent(err)
+					}
+					return err
+				}
+				log.Printf("%s: transaction submitted successfully txHash: %s", feederType, txHash)
+				return nil
+			},
+		})
+	}
+	return jobs, nil
+}
+
+func jobSchedule(cfg config.JobConfig) (daemon.Schedule, error) {
+	switch {
+	case cfg.Cron != "" && cfg.Interval > 0:
+		return nil, fmt.Errorf("both an interval and a cron expression are set")
+	case cfg.Cron != "":
+		return daemon.ParseCron(cfg.Cron)
+	case cfg.Interval > 0:
+		return daemon.Every(time.Duration(cfg.Interval) * time.Second), nil
+	default:
+		return nil, fmt.Errorf("neither an interval nor a cron expression is set")
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	alliance_provider "github.com/terra-money/oracle-feeder-go/internal/provider/alliance"
+)
+
+type dryRunner interface {
+	DryRun(ctx context.Context) (*alliance_provider.DryRunResult, error)
+}
+
+// runDryRun prints the transaction the feeder would submit, it is never broadcast.
+func runDryRun(ctx context.Context, querier dryRunner) error {
+	res, err := querier.DryRun(ctx)
+	if err != nil {
+		return fmt.Errorf("dry run failed: %w", err)
+	}
+	tx := res.Tx
+	fmt.Printf("Sender:   %s\n", tx.Msg.Sender)
+	fmt.Printf("Contract: %s\n", tx.Msg.Contract)
+	fmt.Printf("Account:  number %d, sequence %d\n", tx.AccountNumber, tx.Sequence)
+	fmt.Printf("Gas:      %d used, limit %d\n", tx.GasUsed, tx.GasLimit)
+	fmt.Printf("Fee:      %s\n", tx.Fee)
+
+	var msg bytes.Buffer
+	if err := json.Indent(&msg, tx.Msg.Msg, "", "  "); err != nil {
+		msg.Write(tx.Msg.Msg)
+	}
+	fmt.Printf("\nExecute message:\n%s\n", msg.String())
+
+	if len(res.Changes) > 0 {
+		fmt.Println("\nChanges:")
+		for _, change := range res.Changes {
+			fmt.Println(change)
+		}
+	}
+	fmt.Println("\nDry run, nothing was broadcast.")
+	return nil
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -9,8 +11,12 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/daemon"
 	alliance_provider "github.com/terra-money/oracle-feeder-go/internal/provider/alliance"
+	"github.com/terra-money/oracle-feeder-go/internal/reporting"
 	"github.com/terra-money/oracle-feeder-go/internal/types"
+	pkgtypes "github.com/terra-money/oracle-feeder-go/pkg/types"
 )
 
 func main() {
@@ -19,24 +25,65 @@ func main() {
 	if err != nil {
 		log.Print("Error loading .env file:", err)
 	}
+	if err := run(); err != nil {
+		reporting.CaptureError(err, nil)
+		reporting.Flush()
+		log.Fatal(err)
+	}
+	reporting.Flush()
+}
+
+// run executes the command of the cli arguments, the errors
+// and panics once reporting is initialized get reported.
+func run() error {
 	retries := 3
 	if feederRetries := os.Getenv("FEEDER_RETRIES"); feederRetries != "" {
+		var err error
 		retries, err = strconv.Atoi(feederRetries)
 		if err != nil {
-			log.Fatal("Error parsing FEEDER_RETRIES:", err)
+			return fmt.Errorf("parsing FEEDER_RETRIES: %w", err)
 		}
 	}
 	// Read the cli arguments
-	if len(os.Args) != 2 || os.Args[1] == "" {
-		log.Fatal(`Specify the first argument as the feeder type.`)
+	dryRun := flag.Bool("dry-run", false, "simulate the transaction and print it without broadcasting it")
+	exportUnsigned := flag.String("export-unsigned", "", "write the unsigned transaction to the file instead of signing and broadcasting it")
+	flag.Parse()
+	if flag.Arg(0) == "broadcast" && flag.NArg() == 2 {
+		initReporting("")
+		defer reporting.Recover()
+		return runBroadcast(flag.Arg(1))
+	}
+	if flag.NArg() != 1 || flag.Arg(0) == "" {
+		return errors.New("specify the first argument as the feeder type, daemon, or broadcast <signed tx file>")
+	}
+	if *dryRun && *exportUnsigned != "" {
+		return errors.New("--dry-run and --export-unsigned cannot be combined")
 	}
-	feederType, err := types.ParseFeederTypeFromString(os.Args[1])
+	if flag.Arg(0) == "daemon" {
+		if *dryRun || *exportUnsigned != "" {
+			return errors.New("--dry-run and --export-unsigned apply to a single feeder type, not to the daemon")
+		}
+		initReporting("")
+		defer reporting.Recover()
+		return runDaemon()
+	}
+	feederType, err := types.ParseFeederTypeFromString(flag.Arg(0))
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
+	initReporting(feederType)
+	// e.g. the panics of NewTransactionsProvider on a missing env variable
+	defer reporting.Recover()
+
 	ctx := context.Background()
 	alliancesQuerierProvider := alliance_provider.NewAlliancesQuerierProvider(feederType)
+	if *dryRun {
+		return runDryRun(ctx, alliancesQuerierProvider)
+	}
+	if *exportUnsigned != "" {
+		return runExportUnsigned(ctx, alliancesQuerierProvider, *exportUnsigned)
+	}
 
 	for attempt := 1; attempt <= retries; attempt++ {
 		txHash, err := alliancesQuerierProvider.SubmitTx(ctx)
@@ -52,9 +99,37 @@ func main() {
 				fmt.Println("All attempts failed. Exiting...")
 				break
 			}
+			if !retryable(err) {
+				fmt.Println("The error is not retryable. Exiting...")
+				break
+			}
 
 			fmt.Printf("Retrying in 15 seconds...\n")
 			time.Sleep(15 * time.Second)
 		}
 	}
+	return nil
+}
+
+// initReporting reports to Sentry with the tags of the feeder type, none for the daemon and broadcast.
+func initReporting(feederType types.FeederType) {
+	err := reporting.Init(config.DefaultPriceServerConfig.Sentry, map[string]string{
+		"binary":      "feeder",
+		"feeder_type": string(feederType),
+		"chain":       os.Getenv("CHAIN_ID"),
+	})
+	if err != nil {
+		log.Print("Error initializing sentry:", err)
+	}
+}
+
+// retryable tells whether retrying could fix the error, which is not the
+// case when e.g. the price server does not know the request or when the
+// transaction may still be included.
+func retryable(err error) bool {
+	var apiErr pkgtypes.APIError
+	if daemon.IsPermanent(err) {
+		return false
+	}
+	return !errors.As(err, &apiErr) || apiErr.Retryable
 }
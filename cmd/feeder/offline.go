@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/terra-money/oracle-feeder-go/internal/provider"
+)
+
+type unsignedTxBuilder interface {
+	UnsignedTx(ctx context.Context) (*provider.SimulatedTx, error)
+}
+
+// runExportUnsigned writes the unsigned transaction of the feeder type to the
+// file, for SIGNER_ADDRESS, e.g. a multisig, to sign it offline, and what it is
+// signed with to the signing info file next to it.
+func runExportUnsigned(ctx context.Context, querier unsignedTxBuilder, file string) error {
+	tx, err := querier.UnsignedTx(ctx)
+	if err != nil {
+		return fmt.Errorf("building the transaction failed: %w", err)
+	}
+	txJSON, err := tx.JSON()
+	if err != nil {
+		return fmt.Errorf("encoding the transaction failed: %w", err)
+	}
+	signingInfoJSON, err := json.MarshalIndent(tx.SigningInfo(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding the signing info failed: %w", err)
+	}
+	if err := os.WriteFile(file, txJSON, 0o644); err != nil {
+		return err
+	}
+	signingInfoFile := signingInfoPath(file)
+	if err := os.WriteFile(signingInfoFile, signingInfoJSON, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Unsigned transaction of %s written to %s, its signing info to %s\n", tx.Msg.Sender, file, signingInfoFile)
+	fmt.Printf("Chain ID: %s, account number: %d, sequence: %d\n", tx.ChainId, tx.AccountNumber, tx.Sequence)
+	fmt.Printf("Sign it offline with: terrad tx sign %s --from <key> [--multisig %s] --offline --chain-id %s --account-number %d --sequence %d\n",
+		file, tx.Msg.Sender, tx.ChainId, tx.AccountNumber, tx.Sequence)
+	fmt.Println("aggregate the signatures of a multisig with terrad tx multisign, then broadcast with: feeder broadcast <signed tx file>")
+	return nil
+}
+
+// signingInfoPath is the path of the signing info of the transaction file,
+// e.g. unsigned.signing.json for unsigned.json.
+func signingInfoPath(file string) string {
+	return strings.TrimSuffix(file, filepath.Ext(file)) + ".signing.json"
+}
+
+// runBroadcast broadcasts the transaction signed offline in the file.
+func runBroadcast(file string) error {
+	txJSON, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	// the signer and the contract are the ones of the signed transaction
+	broadcaster := provider.NewBroadcaster()
+	txHash, err := broadcaster.BroadcastSignedTransaction(context.Background(), txJSON)
+	if err != nil {
+		return fmt.Errorf("broadcasting the transaction failed: %w", err)
+	}
+	fmt.Printf("Transaction broadcast successfully txHash: %s \n", txHash)
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/joho/godotenv"
+	"github.com/terra-money/oracle-feeder-go/internal/mockexchange"
+)
+
+// defaultScript is served when no script file is given as first argument.
+var defaultScript = mockexchange.Script{
+	IntervalMs: 1000,
+	Prices: map[string][]float64{
+		"BTC/USDT":  {29000, 29100, 29050, 28950},
+		"BTC/USD":   {29000, 29100, 29050, 28950},
+		"ETH/USDT":  {1850, 1855, 1860, 1845},
+		"LUNA/USDT": {0.55, 0.56, 0.555},
+		"USDT/USD":  {1},
+		"USDC/USD":  {1},
+		"EUR/USD":   {1.09},
+	},
+}
+
+func main() {
+	err := godotenv.Load()
+	if err != nil {
+		log.Print("Error loading .env file:", err)
+	}
+
+	script := &defaultScript
+	if len(os.Args) == 2 {
+		script, err = mockexchange.LoadScript(os.Args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	port := os.Getenv("MOCK_EXCHANGE_PORT")
+	if port == "" {
+		port = "8540" // use 8540 by default
+	}
+
+	// Print the variables that point the price server to this mock
+	env := mockexchange.Env(fmt.Sprintf("http://localhost:%s", port))
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("%s=%s\n", key, env[key])
+	}
+
+	server := mockexchange.NewServer(script)
+	err = http.ListenAndServe(":"+port, server.Handler())
+	if err != nil {
+		panic(err)
+	}
+}
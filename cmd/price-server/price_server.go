@@ -2,15 +2,28 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
+	sentrygin "github.com/getsentry/sentry-go/gin"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/api"
+	"github.com/terra-money/oracle-feeder-go/internal/auth"
+	"github.com/terra-money/oracle-feeder-go/internal/grpcserver"
+	"github.com/terra-money/oracle-feeder-go/internal/metrics"
 	"github.com/terra-money/oracle-feeder-go/internal/provider"
 	alliance_provider "github.com/terra-money/oracle-feeder-go/internal/provider/alliance"
+	"github.com/terra-money/oracle-feeder-go/internal/reporting"
+	"github.com/terra-money/oracle-feeder-go/internal/signature"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -18,46 +31,106 @@ func main() {
 	if err != nil {
 		log.Print("Error loading .env file:", err)
 	}
+	if err := reporting.Init(config.DefaultPriceServerConfig.Sentry, map[string]string{"binary": "price-server"}); err != nil {
+		log.Print("Error initializing sentry:", err)
+	}
+	if err := run(); err != nil {
+		reporting.CaptureError(err, nil)
+		reporting.Flush()
+		log.Fatal(err)
+	}
+	reporting.Flush()
+}
+
+// run serves the prices until the server fails, its errors and panics get reported.
+func run() error {
+	defer reporting.Recover()
 	ctx := context.Background()
 
 	stopCh := make(chan struct{})
 	manager := provider.NewProviderManager(&config.DefaultPriceServerConfig, stopCh)
-	allianceProvider := alliance_provider.NewAllianceProvider(&config.AllianceDefaultConfig, manager)
+	allianceProvider := alliance_provider.NewAllianceProvider(&config.AllianceDefaultConfig, manager, stopCh)
 
-	r := gin.Default()
-	r.GET("/health", func(c *gin.Context) {
-		c.String(http.StatusOK, "OK")
-	})
-	r.GET("/latest", func(c *gin.Context) {
-		prices := manager.GetPrices(ctx)
-		c.JSON(http.StatusOK, prices)
-	})
-	r.GET("/alliance/protocol", func(c *gin.Context) {
-		allianceProtocolRes, err := allianceProvider.GetProtocolsInfo(ctx)
-		// allianceProtocolRes.UpdateChainsInfo.ChainsInfo.ProtocolsInfo[0].ChainId = "narwhal-1"
-		// allianceProtocolRes.UpdateChainsInfo.ChainsInfo.ProtocolsInfo[1].ChainId = "harpoon-4"
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, err)
-			return
+	metrics.Registry.MustRegister(metrics.NewDenomCollector(func() map[string]metrics.DenomStats {
+		return manager.GetDenomStats(ctx)
+	}))
+	go func() {
+		defer reporting.Recover()
+		addr := fmt.Sprintf(":%d", config.DefaultPriceServerConfig.MetricsPort)
+		if err := http.ListenAndServe(addr, metrics.Handler()); err != nil {
+			log.Printf("metrics server: %v", err)
 		}
-		c.JSON(http.StatusOK, allianceProtocolRes)
-	})
-	r.GET("/alliance/rebalance", func(c *gin.Context) {
-		allianceRebalanceVals, err := allianceProvider.GetAllianceRedelegateReq(ctx)
+	}()
+
+	authenticator, err := auth.NewAuthenticatorFromEnv(config.DefaultPriceServerConfig.Auth)
+	if err != nil {
+		return fmt.Errorf("loading PRICE_SERVER_API_KEYS: %w", err)
+	}
+
+	grpcServer := grpcserver.NewServer(manager, allianceProvider,
+		grpc.UnaryInterceptor(authenticator.UnaryServerInterceptor(grpcserver.Routes)),
+		grpc.StreamInterceptor(authenticator.StreamServerInterceptor(grpcserver.Routes)),
+	)
+	go func() {
+		defer reporting.Recover()
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", config.DefaultPriceServerConfig.GrpcPort))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, err)
+			log.Printf("grpc server: %v", err)
 			return
 		}
-		c.JSON(http.StatusOK, allianceRebalanceVals)
-	})
-	r.GET("/alliance/delegations", func(c *gin.Context) {
-		allianceDelegatios, err := allianceProvider.GetAllianceInitialDelegations(ctx)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, err)
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Printf("grpc server: %v", err)
+		}
+	}()
+	defer grpcServer.Stop()
+
+	r := gin.New()
+	r.Use(gin.LoggerWithFormatter(auth.LogFormatter), gin.Recovery())
+	r.Use(metrics.GinMiddleware())
+	// report the panics of the handlers, e.g. of the carbon provider, before gin recovers them
+	r.Use(sentrygin.New(sentrygin.Options{Repanic: true}))
+	// registered before the signing middleware which would hold the events back
+	r.GET("/stream", authenticator.Middleware(), func(c *gin.Context) {
+		var denoms []string
+		if value := c.Query("denoms"); value != "" {
+			denoms = strings.Split(value, ",")
+		}
+		minChange, err := strconv.ParseFloat(c.DefaultQuery("min_change", "0"), 64)
+		if err != nil || minChange < 0 {
+			api.WriteError(c, api.BadRequest("invalid min_change %q", c.Query("min_change")))
 			return
 		}
-		c.JSON(http.StatusOK, allianceDelegatios)
+		prices := manager.StreamPrices(c.Request.Context(), denoms, minChange)
+		c.Stream(func(w io.Writer) bool {
+			price, ok := <-prices
+			if !ok {
+				return false
+			}
+			c.SSEvent("price", price)
+			return true
+		})
+	})
+	signer, err := signature.NewSignerFromEnv()
+	if err != nil {
+		return fmt.Errorf("loading PRICE_SERVER_PRIVATE_KEY: %w", err)
+	}
+	if signer != nil {
+		log.Printf("Signing the responses with the public key %s", signer.PubKey())
+		r.Use(signer.Middleware())
+	}
+	// the probes are registered before the authentication, a "/" group must not protect them
+	r.GET("/health", func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
 	})
+	r.GET("/ready", readyHandler(manager, allianceProvider.GetReadiness, config.DefaultPriceServerConfig.Readiness))
+	// registered after the signing middleware for the 401 and 429 responses to be signed
+	r.Use(authenticator.Middleware())
+	// the unversioned routes are kept for the feeders that predate /v1
+	serverRoutes := routes(manager, allianceProvider)
+	api.Register(r, serverRoutes)
+	v1 := r.Group("/v1")
+	api.Register(v1, serverRoutes)
+	v1.GET("/openapi.json", api.OpenAPIHandler(api.OpenAPI("Price Server", "v1", "/v1", serverRoutes)))
 	if os.Getenv("PRICE_SERVER_PORT") == "" {
 		os.Setenv("PORT", "8532") // use 8532 by default
 	} else {
@@ -65,9 +138,6 @@ func main() {
 	}
 
 	err = r.Run()
-	if err != nil {
-		panic(err)
-	}
-
 	close(stopCh)
+	return err
 }
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/api"
+	"github.com/terra-money/oracle-feeder-go/internal/grpcserver"
+	"github.com/terra-money/oracle-feeder-go/internal/provider"
+	"github.com/terra-money/oracle-feeder-go/internal/signature"
+	pkgtypes "github.com/terra-money/oracle-feeder-go/pkg/types"
+)
+
+// routes are the JSON endpoints of the price server.
+func routes(manager *provider.ProviderManager, allianceProvider grpcserver.AllianceProvider) []api.Route {
+	return []api.Route{
+		{
+			Method:   http.MethodGet,
+			Path:     "/latest",
+			Summary:  "Aggregated USD price of every denom",
+			Response: pkgtypes.PricesResponse{},
+			Handler: func(c *gin.Context) (any, error) {
+				return manager.GetPrices(c.Request.Context()), nil
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/latest/:denom",
+			Summary:  "Aggregated USD price of a denom",
+			Response: pkgtypes.PriceResponse{},
+			Handler: func(c *gin.Context) (any, error) {
+				res := manager.GetPrice(c.Request.Context(), c.Param("denom"))
+				if res == nil {
+					return nil, api.NotFound("no price for %s", c.Param("denom"))
+				}
+				return res, nil
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/status",
+			Summary:  "Prices held by each provider",
+			Response: pkgtypes.StatusResponse{},
+			Handler: func(c *gin.Context) (any, error) {
+				return manager.GetStatus(c.Request.Context()), nil
+			},
+		},
+		// the alliance messages are computed in the background, the request
+		// fails only when none could be computed since the server started
+		{
+			Method:   http.MethodGet,
+			Path:     "/alliance/protocol",
+			Summary:  "update_chains_info message of the alliance oracle",
+			Response: pkgtypes.MsgUpdateChainsInfo{},
+			Handler: func(c *gin.Context) (any, error) {
+				res, err := allianceProvider.GetProtocolsInfo(c.Request.Context())
+				if err != nil {
+					return nil, err
+				}
+				setCacheHeaders(c, res.AsOf, res.Stale)
+				return res.Value, nil
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/alliance/rebalance",
+			Summary:  "alliance_redelegate message of the alliance hub",
+			Response: pkgtypes.MsgAllianceRedelegate{},
+			Handler: func(c *gin.Context) (any, error) {
+				res, err := allianceProvider.GetAllianceRedelegateReq(c.Request.Context())
+				if err != nil {
+					return nil, err
+				}
+				setCacheHeaders(c, res.AsOf, res.Stale)
+				return res.Value, nil
+			},
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/alliance/delegations",
+			Summary:  "alliance_delegate message of the alliance hub",
+			Response: pkgtypes.MsgAllianceDelegations{},
+			Handler: func(c *gin.Context) (any, error) {
+				res, err := allianceProvider.GetAllianceInitialDelegations(c.Request.Context())
+				if err != nil {
+					return nil, err
+				}
+				setCacheHeaders(c, res.AsOf, res.Stale)
+				return res.Value, nil
+			},
+		},
+	}
+}
+
+// readyHandler answers 503 with the failing checks until the price server
+// serves fresh prices and, if required, alliance messages, so that
+// Kubernetes only routes traffic to a ready server.
+func readyHandler(manager *provider.ProviderManager, allianceChecks func() []pkgtypes.ReadyCheck, cfg config.ReadinessConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		checks := manager.GetReadiness(c.Request.Context())
+		if cfg.Alliance {
+			checks = append(checks, allianceChecks()...)
+		}
+		res := pkgtypes.ReadyResponse{Ready: true, Checks: checks}
+		for _, check := range checks {
+			res.Ready = res.Ready && check.Ok
+		}
+		if !res.Ready {
+			c.JSON(http.StatusServiceUnavailable, res)
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+// setCacheHeaders tells when an alliance message was computed and whether it is stale,
+// the body being the contract message as is.
+func setCacheHeaders(c *gin.Context, asOf time.Time, stale bool) {
+	c.Header(signature.AsOfHeader, asOf.UTC().Format(time.RFC3339))
+	c.Header(signature.StaleHeader, strconv.FormatBool(stale))
+}
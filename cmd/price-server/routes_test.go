@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/api"
+	"github.com/terra-money/oracle-feeder-go/internal/mockexchange"
+	"github.com/terra-money/oracle-feeder-go/internal/provider"
+	pkgtypes "github.com/terra-money/oracle-feeder-go/pkg/types"
+)
+
+func TestLatestPrices(t *testing.T) {
+	// GIVEN every exchange served by the mock exchange
+	server := httptest.NewServer(mockexchange.NewServer(&mockexchange.Script{
+		IntervalMs:      50,
+		DisconnectAfter: 10,
+		MalformedEvery:  3,
+		Prices: map[string][]float64{
+			"BTC/USDT": {30000, 30200},
+			"BTC/USD":  {30100},
+			"ETH/USDT": {2000},
+			"USDT/USD": {1},
+			"EUR/USD":  {1.1},
+		},
+	}).Handler())
+	defer server.Close()
+	for key, value := range mockexchange.Env(server.URL) {
+		t.Setenv(key, value)
+	}
+	cfg := &config.Config{
+		ProviderPriority: []string{"binance", "kraken", "okx", "kucoin", "bitstamp", "coingecko", "exchangerate", "fer", "frankfurter"},
+		Providers: map[string]config.ProviderConfig{
+			"binance":      {Symbols: []string{"BTCUSDT", "ETHUSDT"}},
+			"kraken":       {Symbols: []string{"XBT/USD"}},
+			"okx":          {Symbols: []string{"BTC-USDT"}},
+			"kucoin":       {Symbols: []string{"BTC-USDT", "ETH-USDT"}},
+			"bitstamp":     {Symbols: []string{"btcusd", "usdtusd"}, Interval: 1, Timeout: 1},
+			"coingecko":    {Symbols: []string{"bitcoin", "tether"}, Interval: 1, Timeout: 1},
+			"exchangerate": {Symbols: []string{"EUR"}, Interval: 1, Timeout: 1},
+			"fer":          {Symbols: []string{"EUR"}, Interval: 1, Timeout: 1},
+			"frankfurter":  {Symbols: []string{"EUR"}, Interval: 1, Timeout: 1},
+		},
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	manager := provider.NewProviderManager(cfg, stopCh)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	api.Register(r, routes(manager, nil))
+	api.Register(r.Group("/v1"), routes(manager, nil))
+	get := func(path string, res any) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+		}
+		return w.Code
+	}
+
+	// WHEN
+	prices := make(map[string]float64)
+	require.Eventually(t, func() bool {
+		var res pkgtypes.PricesResponse
+		require.Equal(t, http.StatusOK, get("/latest", &res))
+		for _, price := range res.Prices {
+			prices[price.Denom] = price.Price
+		}
+		return len(prices) == 4
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// THEN
+	require.InDelta(t, 30100, prices["BTC"], 100)
+	require.InDelta(t, 2000, prices["ETH"], 1e-9)
+	require.InDelta(t, 1, prices["USDT"], 1e-9)
+	require.InDelta(t, 1.1, prices["EUR"], 1e-9)
+	// the versioned route answers the same prices
+	var res pkgtypes.PricesResponse
+	require.Equal(t, http.StatusOK, get("/v1/latest", &res))
+	require.Len(t, res.Prices, 4)
+	var price pkgtypes.PriceResponse
+	require.Equal(t, http.StatusOK, get("/v1/latest/eth", &price))
+	require.InDelta(t, 2000, price.Price.Price, 1e-9)
+	require.Equal(t, http.StatusNotFound, get("/v1/latest/atom", nil))
+}
@@ -6,7 +6,10 @@ var KUJIRA_GRPC = "kujira-grpc.polkachu.com:11890"
 var CARBON_GRPC = "carbon-grpc.polkachu.com:19690"
 
 var AllianceDefaultConfig = AllianceConfig{
-	GRPCUrls: []string{MIGALOO_GRPC, KUJIRA_GRPC, CARBON_GRPC},
+	GRPCUrls:        []string{MIGALOO_GRPC, KUJIRA_GRPC, CARBON_GRPC},
+	RefreshInterval: 60,
+	CacheTTL:        300,
+	CacheMaxAge:     1800,
 	LSTSData: []LSTData{
 		// Whale
 		{ // Eris Protocol ampLUNA https://chainsco.pe/terra2/address/terra1ecgazyd0waaj3g7l9cmy5gulhxkps2gmxu9ghducvuypjq68mq2s5lvsct
@@ -1,27 +1,114 @@
 package config
 
 import (
+	"time"
+
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
 )
 
 type Config struct {
 	Port             int                       `json:"port,omitempty"`
 	MetricsPort      int                       `json:"metrics_port,omitempty"`
+	GrpcPort         int                       `json:"grpc_port,omitempty"`
 	Sentry           string                    `json:"sentry,omitempty"` // sentry dsn (https://sentry.io/ - error reporting service)
 	Providers        map[string]ProviderConfig `json:"providers,omitempty"`
 	ProviderPriority []string                  `json:"provider_prioirty,omitempty"`
+	Reference        ReferenceConfig           `json:"reference,omitempty"`
+	Readiness        ReadinessConfig           `json:"readiness,omitempty"`
+	Auth             AuthConfig                `json:"auth,omitempty"`
+}
+
+// ReferenceConfig cross-checks the aggregated prices against an independent oracle.
+type ReferenceConfig struct {
+	Provider     string  `json:"provider,omitempty"`      // e.g. pyth, configured in Providers but left out of the averages
+	Fallback     string  `json:"fallback,omitempty"`      // e.g. coingecko, averaged provider the denoms missing from Provider are checked against
+	MaxDeviation float64 `json:"max_deviation,omitempty"` // fraction, e.g. 0.05, above which a denom diverges from the reference
+	Halt         bool    `json:"halt,omitempty"`          // withhold the divergent denoms instead of flagging them
+}
+
+// ReadinessConfig is what GET /ready requires before the price server takes traffic.
+type ReadinessConfig struct {
+	MinProviders int      `json:"min_providers,omitempty"` // providers serving at least one fresh price
+	Denoms       []string `json:"denoms,omitempty"`        // denoms that must have a fresh price
+	Quorum       int      `json:"quorum,omitempty"`        // fresh provider prices each denom must be aggregated from
+	MaxAge       int      `json:"max_age,omitempty"`       // in seconds, age above which a provider price is not fresh
+	Alliance     bool     `json:"alliance,omitempty"`      // the last refresh of every alliance message must have succeeded
+}
+
+// AuthConfig protects route groups of the price server with the API keys of PRICE_SERVER_API_KEYS.
+type AuthConfig struct {
+	Groups     map[string]string          `json:"groups,omitempty"`      // path prefix, e.g. /v1/alliance -> api_key or hmac, the longest prefix applies
+	RateLimit  RateLimitConfig            `json:"rate_limit,omitempty"`  // of each key
+	RateLimits map[string]RateLimitConfig `json:"rate_limits,omitempty"` // key ID -> rate limit replacing RateLimit
+}
+
+// RateLimitConfig is the token bucket of an API key.
+type RateLimitConfig struct {
+	Rate  float64 `json:"rate,omitempty"`  // requests per second, unlimited when zero
+	Burst int     `json:"burst,omitempty"` // requests the key can send at once, 1 by default
 }
 
 type ProviderConfig struct {
-	Symbols  []string `json:"symbols,omitempty"`
-	Interval int      `json:"interval,omitempty"` // in seconds
-	Timeout  int      `json:"timeout,omitempty"`
+	Symbols        []string           `json:"symbols,omitempty"`
+	Interval       int                `json:"interval,omitempty"` // in seconds
+	Timeout        int                `json:"timeout,omitempty"`
+	Urls           []string           `json:"urls,omitempty"`             // nodes queried by on-chain providers, tried in order
+	Pools          []PoolConfig       `json:"pools,omitempty"`            // liquidity pools queried by on-chain providers
+	TwapWindow     int                `json:"twap_window,omitempty"`      // in seconds, query the on-chain TWAP instead of the spot price
+	TradeSizes     map[string]float64 `json:"trade_sizes,omitempty"`      // unified pair -> amount of base coin of the simulated swaps, 1 by default
+	MaxPriceImpact float64            `json:"max_price_impact,omitempty"` // fraction, e.g. 0.02, above which simulated quotes are rejected
+	Mode           string             `json:"mode,omitempty"`             // websocket exchanges: kline (default), book_ticker for the mid price of the order book or trades
+	Window         int                `json:"window,omitempty"`           // in seconds, VWAP window of the trades mode, 60 by default
+	MaxStaleAge    int                `json:"max_stale_age,omitempty"`    // in seconds, on-chain providers: age above which the last price of a failing pool is withdrawn, 600 by default
+}
+
+// DefaultInterval is the polling interval in seconds of the providers configuring none.
+const DefaultInterval = 30
+
+// PollInterval is the configured interval, DefaultInterval when unset.
+func (c *ProviderConfig) PollInterval() time.Duration {
+	if c.Interval <= 0 {
+		return DefaultInterval * time.Second
+	}
+	return time.Duration(c.Interval) * time.Second
+}
+
+// PoolConfig maps an on-chain liquidity pool to the pair it prices.
+type PoolConfig struct {
+	Pair          string `json:"pair"`                     // unified pair, e.g. ETH/USDC
+	Id            string `json:"id"`                       // pool id or pool contract address
+	BaseDenom     string `json:"base_denom,omitempty"`     // on-chain denom or token address of the base coin
+	QuoteDenom    string `json:"quote_denom,omitempty"`    // on-chain denom or token address of the quote coin
+	BaseExponent  int    `json:"base_exponent,omitempty"`  // decimals of the base denom, when they can't be queried
+	QuoteExponent int    `json:"quote_exponent,omitempty"` // decimals of the quote denom, when they can't be queried
+	Query         string `json:"query,omitempty"`          // simulation (default) or pool, for Astroport pairs
 }
 
 type AllianceConfig struct {
 	GRPCUrls     []string       `json:"lcdList,omitempty"`
 	LSTSData     []LSTData      `json:"lstData,omitempty"`
 	LSTOnPhoenix []LSTOnPhoenix `json:"lstOnPhoenix,omitempty"`
+	// in seconds, how often the alliance messages are computed in the background
+	RefreshInterval int `json:"refreshInterval,omitempty"`
+	// in seconds, age above which the alliance messages are flagged as stale
+	CacheTTL int `json:"cacheTTL,omitempty"`
+	// in seconds, age above which the alliance messages are not served anymore, 0 to serve them forever
+	CacheMaxAge int `json:"cacheMaxAge,omitempty"`
+}
+
+// FeederConfig schedules the jobs of the feeder daemon.
+type FeederConfig struct {
+	Jobs map[string]JobConfig `json:"jobs,omitempty"` // feeder type -> job, the feeder types left out are not run
+}
+
+// JobConfig is the schedule and the retry policy of a feeder type.
+type JobConfig struct {
+	Interval      int    `json:"interval,omitempty"`        // in seconds, between two runs
+	Cron          string `json:"cron,omitempty"`            // cron expression in UTC, e.g. "0 */6 * * *", instead of the interval
+	Jitter        int    `json:"jitter,omitempty"`          // in seconds, maximum random delay added to each run
+	Retries       int    `json:"retries,omitempty"`         // attempts after a failed one
+	RetryDelay    int    `json:"retry_delay,omitempty"`     // in seconds, before the first retry, doubled for each next one
+	MaxRetryDelay int    `json:"max_retry_delay,omitempty"` // in seconds
 }
 
 type LSTData struct {
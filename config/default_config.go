@@ -3,12 +3,14 @@ package config
 var DefaultPriceServerConfig = Config{
 	Port:             8532,
 	MetricsPort:      8533,
+	GrpcPort:         8534,
 	Sentry:           "",
-	ProviderPriority: []string{"astroport", "binance", "huobi", "kucoin", "bitfinex", "kraken", "okx", "coingecko", "osmosis", "bitstamp", "bybit" /*"bittrex",*/, "exchangerate", "frankfurter", "fer"},
+	ProviderPriority: []string{"astroport", "binance", "huobi", "kucoin", "bitfinex", "kraken", "okx", "gateio", "mexc", "cryptocom", "coingecko", "osmosis", "uniswap", "kujira", "whitewhale", "bitstamp", "bybit" /*"bittrex",*/, "exchangerate", "frankfurter", "fer"},
 	Providers: map[string]ProviderConfig{
 		"astroport": {
-			Interval: 30,
-			Timeout:  10,
+			Interval:       30,
+			Timeout:        10,
+			MaxPriceImpact: 0.05,
 			Symbols: []string{
 				"ibc/08095CEDEA29977C9DD0CE9A48329FDA622C183359D5F90CF04CC4FF80CBE431-ibc/B3504E092456BA618CC28AC671A71FB08C6CA0FD0BE7C8A5B5A3E2DD933CC9E4", // stLuna  - axlUSDC
 				"ibc/08095CEDEA29977C9DD0CE9A48329FDA622C183359D5F90CF04CC4FF80CBE431-ibc/CBF67A2BCF6CAE343FDF251E510C8E18C361FC02B23430C121116E0811835DEF", // stLuna  - axlUSDT
@@ -1188,6 +1190,56 @@ var DefaultPriceServerConfig = Config{
 				"ETH-EURT",
 			},
 		},
+		"gateio": {
+			Symbols: []string{
+				"BTC_USDT",
+				"ETH_USDT",
+				"ATOM_USDT",
+				"LUNA_USDT",
+				"OSMO_USDT",
+				"INJ_USDT",
+				"AKT_USDT",
+				"KAVA_USDT",
+				"SCRT_USDT",
+				"JUNO_USDT",
+				"STARS_USDT",
+				"KUJI_USDT",
+				"WHALE_USDT",
+			},
+		},
+		"mexc": {
+			Symbols: []string{
+				"BTCUSDT",
+				"ETHUSDT",
+				"ATOMUSDT",
+				"LUNAUSDT",
+				"OSMOUSDT",
+				"INJUSDT",
+				"AKTUSDT",
+				"KAVAUSDT",
+				"SCRTUSDT",
+				"JUNOUSDT",
+				"STARSUSDT",
+				"KUJIUSDT",
+				"WHALEUSDT",
+				"SWTHUSDT",
+				"ROARUSDT",
+			},
+		},
+		"cryptocom": {
+			Symbols: []string{
+				"BTC_USDT",
+				"ETH_USDT",
+				"ATOM_USDT",
+				"LUNA_USDT",
+				"OSMO_USDT",
+				"INJ_USDT",
+				"AKT_USDT",
+				"KAVA_USDT",
+				"SCRT_USDT",
+				"JUNO_USDT",
+			},
+		},
 		"coingecko": {
 			Interval: 30,
 			Timeout:  10,
@@ -1212,6 +1264,7 @@ var DefaultPriceServerConfig = Config{
 				"stargaze",
 				"akash-network",
 				"white-whale",
+				"kujira",
 				"eris-amplified-whale",
 				"switcheo",
 				"stafi-staked-swth",
@@ -1222,20 +1275,204 @@ var DefaultPriceServerConfig = Config{
 		},
 		"osmosis": {
 			Interval: 30,
-			Symbols: []string{
-				"ATOM/OSMO",
-				"AKT/OSMO",
-				"JUNO/OSMO",
-				"SCRT/OSMO",
-				"STARS/OSMO",
-				"USDC/OSMO",
-				"INJ/OSMO",
-				"LUNA/OSMO",
-				"KAVA/OSMO",
-				"LINK/OSMO",
-				"LUNC/OSMO",
-				"ASH/USDC",
-				"OSMO/USDC",
+			Timeout:  10,
+			Urls: []string{
+				"osmosis-grpc.polkachu.com:12590",
+				"grpc.osmosis.zone:9090",
+			},
+			Pools: []PoolConfig{
+				{
+					Pair:          "ATOM/OSMO",
+					Id:            "1",
+					BaseDenom:     "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2",
+					BaseExponent:  6,
+					QuoteDenom:    "uosmo",
+					QuoteExponent: 6,
+				},
+				{
+					Pair:          "AKT/OSMO",
+					Id:            "3",
+					BaseDenom:     "ibc/1480B8FD20AD5FCAE81EA87584D269547DD4D436843C1D20F15E00EB64743EF4",
+					BaseExponent:  6,
+					QuoteDenom:    "uosmo",
+					QuoteExponent: 6,
+				},
+				{
+					Pair:          "JUNO/OSMO",
+					Id:            "497",
+					BaseDenom:     "ibc/46B44899322F3CD854D2D46DEEF881958467CDD4B3B10086DA49296BBED94BED",
+					BaseExponent:  6,
+					QuoteDenom:    "uosmo",
+					QuoteExponent: 6,
+				},
+				{
+					Pair:          "SCRT/OSMO",
+					Id:            "584",
+					BaseDenom:     "ibc/0954E1C28EB7AF5B72D24F3BC2B47BBB2FDF91BDDFD57B74B99E133AED40972A",
+					BaseExponent:  6,
+					QuoteDenom:    "uosmo",
+					QuoteExponent: 6,
+				},
+				{
+					Pair:          "STARS/OSMO",
+					Id:            "604",
+					BaseDenom:     "ibc/987C17B11ABC2B20019178ACE62929FE9840202CE79498E29FE8E5CB02B7C0A4",
+					BaseExponent:  6,
+					QuoteDenom:    "uosmo",
+					QuoteExponent: 6,
+				},
+				{
+					Pair:          "USDC/OSMO",
+					Id:            "678",
+					BaseDenom:     "ibc/D189335C6E4A68B513C10AB227BF1C1D38C746766278BA3EEB4FB14124F1D858",
+					BaseExponent:  6,
+					QuoteDenom:    "uosmo",
+					QuoteExponent: 6,
+				},
+				{
+					Pair:          "INJ/OSMO",
+					Id:            "725",
+					BaseDenom:     "ibc/64BA6E31FE887D66C6F8F31C7B1A80C7CA179239677B4088BB55F5EA07DBE273",
+					BaseExponent:  18,
+					QuoteDenom:    "uosmo",
+					QuoteExponent: 6,
+				},
+				{
+					Pair:          "LUNA/OSMO",
+					Id:            "726",
+					BaseDenom:     "ibc/785AFEC6B3741100D15E7AF01374E3C4C36F24888E96479B1C33F5C71F364EF9",
+					BaseExponent:  6,
+					QuoteDenom:    "uosmo",
+					QuoteExponent: 6,
+				},
+				{
+					Pair:          "KAVA/OSMO",
+					Id:            "730",
+					BaseDenom:     "ibc/57AA1A70A4BC9769C525EBF6386F7A21536E04A79D62E1981EFCEF9428EBB205",
+					BaseExponent:  6,
+					QuoteDenom:    "uosmo",
+					QuoteExponent: 6,
+				},
+				{
+					Pair:          "LINK/OSMO",
+					Id:            "731",
+					BaseDenom:     "ibc/D3327A763C23F01EC43D1F0DB3CEFEC390C362569B6FD191F40A5192F8960049",
+					BaseExponent:  18,
+					QuoteDenom:    "uosmo",
+					QuoteExponent: 6,
+				},
+				{
+					Pair:          "LUNC/OSMO",
+					Id:            "800",
+					BaseDenom:     "ibc/0EF15DF2F02480ADE0BB6E85D9EBB5DAEA2836D3860E9F97F9AADE4F57A31AA0",
+					BaseExponent:  6,
+					QuoteDenom:    "uosmo",
+					QuoteExponent: 6,
+				},
+				{
+					Pair:          "ASH/USDC",
+					Id:            "1360",
+					BaseDenom:     "ibc/4976049456D261659D0EC499CC9C2391D3C7D1128A0B9FB0BBF2842D1B2BC7BC", // migaloo ash
+					BaseExponent:  6,
+					QuoteDenom:    "ibc/498A0751C798A0D9A389AA3691123DADA57DAA4FE165D5C75894505B876BA6E4",
+					QuoteExponent: 6,
+				},
+				{
+					Pair:          "OSMO/USDC",
+					Id:            "1464",
+					BaseDenom:     "uosmo",
+					BaseExponent:  6,
+					QuoteDenom:    "ibc/498A0751C798A0D9A389AA3691123DADA57DAA4FE165D5C75894505B876BA6E4",
+					QuoteExponent: 6,
+				},
+			},
+		},
+		"kujira": {
+			Interval:       30,
+			Timeout:        10,
+			TradeSizes:     map[string]float64{"KUJI/USDC": 1000},
+			MaxPriceImpact: 0.05,
+			Pools: []PoolConfig{
+				{
+					Pair:          "KUJI/USDC",
+					Id:            "kujira14hj2tavq8fpesdwxxcu44rty3hh90vhujrvcmstl4zr3txmfvw9sl4e867", // FIN KUJI/axlUSDC
+					BaseExponent:  6,
+					QuoteExponent: 6,
+				},
+			},
+		},
+		"whitewhale": {
+			Interval:       30,
+			Timeout:        10,
+			TradeSizes:     map[string]float64{"WHALE/USDC": 1000, "AMPWHALE/WHALE": 1000, "BWHALE/WHALE": 1000},
+			MaxPriceImpact: 0.05,
+			Pools: []PoolConfig{
+				{
+					Pair:          "WHALE/USDC",
+					Id:            "migaloo1xv4ql6t6r8zawlqn2tyxqsrvjpmjfm6kvdfvytaueqe3qvcwyr7shtx0hj", // WHALE/axlUSDC
+					BaseDenom:     "uwhale",
+					BaseExponent:  6,
+					QuoteExponent: 6,
+				},
+				{
+					Pair:          "AMPWHALE/WHALE",
+					Id:            "migaloo1dg5jrt89nddtymjx5pzrvdvdt0m4zl3l2l3ytunl6a0kqd7k8hss594wy6",
+					BaseDenom:     "factory/migaloo1436kxs0w2es6xlqpp9rd35e3d0cjnw4sv8j3a7483sgks29jqwgshqdky4/ampWHALE",
+					BaseExponent:  6,
+					QuoteDenom:    "uwhale",
+					QuoteExponent: 6,
+				},
+				{ // bWHALE, the LST of the Backbone Labs stake hub
+					Pair:          "BWHALE/WHALE",
+					Id:            "migaloo1ull9s4el2pmkdevdgrjt6pwa4e5xhkda40w84kghftnlxg4h3knqpm5u3n",
+					BaseDenom:     "factory/migaloo1mf6ptkssddfmxvhdx0ech0k03ktp6kf9yk59renau2gvht3nq2gqdhts4u/boneWhale",
+					BaseExponent:  6,
+					QuoteDenom:    "uwhale",
+					QuoteExponent: 6,
+				},
+			},
+		},
+		"uniswap": {
+			Interval: 30,
+			Timeout:  10,
+			Urls: []string{
+				"https://eth.llamarpc.com",
+				"https://rpc.ankr.com/eth",
+				"https://cloudflare-eth.com",
+			},
+			Pools: []PoolConfig{
+				// axlWETH and axlUSDC are backed by the WETH and USDC the Axelar gateway
+				// locks on Ethereum, where those trade deepest
+				{
+					Pair:       "AXLWETH/USDC",
+					Id:         "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640",
+					BaseDenom:  "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2", // WETH
+					QuoteDenom: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", // USDC
+				},
+				{
+					Pair:       "AXLUSDC/USDT",
+					Id:         "0x3416cF6C708Da44DB2624D63ea0AAef7113527C6",
+					BaseDenom:  "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", // USDC
+					QuoteDenom: "0xdAC17F958D2ee523a2206206994597C13D831ec7", // USDT
+				},
+				{
+					Pair:       "ETH/USDC",
+					Id:         "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640",
+					BaseDenom:  "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2", // WETH
+					QuoteDenom: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", // USDC
+				},
+				{
+					Pair:       "ETH/USDT",
+					Id:         "0x4e68Ccd3E89f51C3074ca5072bbAC773960dFa36",
+					BaseDenom:  "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2", // WETH
+					QuoteDenom: "0xdAC17F958D2ee523a2206206994597C13D831ec7", // USDT
+				},
+				{
+					Pair:       "USDC/USDT",
+					Id:         "0x3416cF6C708Da44DB2624D63ea0AAef7113527C6",
+					BaseDenom:  "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", // USDC
+					QuoteDenom: "0xdAC17F958D2ee523a2206206994597C13D831ec7", // USDT
+				},
 			},
 		},
 		"coinbase": {
@@ -1912,5 +2149,37 @@ var DefaultPriceServerConfig = Config{
 			Timeout:  10,
 			Symbols:  FiatCoins,
 		},
+		"pyth": {
+			Interval: 30,
+			Timeout:  10,
+			Symbols: []string{
+				"e62df6c8b4a85fe1a67db44dc12de5db330f7ac66b72dc658afcf5ed4f0a4f3b", // BTC/USD
+				"ff61491a931112ddf1bd8147cd1b641375f79f5825126d665480874634fd0ace", // ETH/USD
+				"ef0d8b6fda2ceba41da15d4095d1da392a0d2f8ed0c6c7bc0f4cfac8c280b56d", // SOL/USD
+				"b00b60f88b03a6a625a8d1c048c3f66653edf217439983d037e7222c4e612819", // ATOM/USD
+				"eaa020c61cc479712813461ce153894a96a6c00b21ed0cfc2798d1f9a9e9c94a", // USDC/USD
+				"2b89b9dc8fdf9f34709a5b106b472f0f39bb6ca9ce04b0fd7f2e971688e2e53b", // USDT/USD
+				"e6ccd3f878cf338e6732bf59f60943e8ca2c28402fc4d9c258503b2edbe74a31", // LUNA/USD
+			},
+		},
+	},
+	Reference: ReferenceConfig{
+		Provider: "pyth",
+		// WHALE, KUJI and SWTH have no Pyth feed
+		Fallback:     "coingecko",
+		MaxDeviation: 0.1,
+	},
+	Readiness: ReadinessConfig{
+		MinProviders: 3,
+		// LUNA and the bond denoms of the chains of AllianceDefaultConfig
+		Denoms:   []string{"LUNA", "WHALE", "KUJI", "SWTH"},
+		Quorum:   1,
+		MaxAge:   300,
+		Alliance: true,
+	},
+	Auth: AuthConfig{
+		// e.g. {"/alliance": "hmac", "/latest": "api_key"}, applying with and without /v1, every route is open by default
+		Groups:    map[string]string{},
+		RateLimit: RateLimitConfig{Rate: 5, Burst: 20},
 	},
 }
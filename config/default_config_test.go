@@ -0,0 +1,34 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/parser"
+)
+
+func TestReferenceFeeds(t *testing.T) {
+	// GIVEN the denoms the chains info is signed with
+	cfg := config.DefaultPriceServerConfig
+	denoms := cfg.Readiness.Denoms
+
+	// WHEN the feeds of the reference and its fallback are parsed
+	references := make(map[string]bool)
+	for _, exchange := range []string{cfg.Reference.Provider, cfg.Reference.Fallback} {
+		for _, symbol := range cfg.Providers[exchange].Symbols {
+			base, quote, err := parser.ParseSymbol(exchange, symbol)
+			require.NoError(t, err)
+			if quote == "USD" {
+				references[base] = true
+			}
+		}
+	}
+
+	// THEN every denom is checked against a reference
+	require.NotEmpty(t, denoms)
+	for _, denom := range denoms {
+		require.True(t, references[denom], "no reference feed for %s", denom)
+	}
+	require.Contains(t, config.DefaultPriceServerConfig.ProviderPriority, cfg.Reference.Fallback)
+}
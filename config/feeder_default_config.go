@@ -0,0 +1,35 @@
+package config
+
+var DefaultFeederConfig = FeederConfig{
+	Jobs: map[string]JobConfig{
+		"alliance-oracle-feeder": {
+			Interval:      3600,
+			Jitter:        60,
+			Retries:       3,
+			RetryDelay:    15,
+			MaxRetryDelay: 120,
+		},
+		"alliance-rebalance-feeder": {
+			Cron:          "0 12 * * *",
+			Jitter:        300,
+			Retries:       3,
+			RetryDelay:    60,
+			MaxRetryDelay: 600,
+		},
+		"alliance-update-rewards": {
+			Cron:          "0 */6 * * *",
+			Jitter:        60,
+			Retries:       3,
+			RetryDelay:    15,
+			MaxRetryDelay: 120,
+		},
+		"alliance-rebalance-emissions": {
+			Cron:          "30 */6 * * *",
+			Jitter:        60,
+			Retries:       3,
+			RetryDelay:    15,
+			MaxRetryDelay: 120,
+		},
+		// the initial delegation is run once by hand, see the Feeder CLI
+	},
+}
@@ -0,0 +1,101 @@
+// Package api serves the versioned HTTP API of the price server and
+// describes it with an OpenAPI document generated from its routes.
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/terra-money/oracle-feeder-go/internal/types"
+	pkgtypes "github.com/terra-money/oracle-feeder-go/pkg/types"
+)
+
+// Route is an endpoint of the API. Response is a value of the type the
+// handler returns, the OpenAPI schema of the route is generated from it.
+type Route struct {
+	Method   string
+	Path     string // gin path, e.g. /latest/:denom
+	Summary  string
+	Response any
+	Handler  func(c *gin.Context) (any, error)
+}
+
+// Register adds the routes to the group, the errors of the
+// handlers are answered with an ErrorResponse.
+func Register(group gin.IRoutes, routes []Route) {
+	for _, route := range routes {
+		handler := route.Handler
+		group.Handle(route.Method, route.Path, func(c *gin.Context) {
+			res, err := handler(c)
+			if err != nil {
+				WriteError(c, err)
+				return
+			}
+			c.JSON(http.StatusOK, res)
+		})
+	}
+}
+
+// WriteError answers the request with the status and the ErrorResponse of the error.
+func WriteError(c *gin.Context, err error) {
+	status, apiErr := FromError(err)
+	c.AbortWithStatusJSON(status, pkgtypes.ErrorResponse{Error: apiErr})
+}
+
+// requestError is an error of the request itself, e.g. an unknown denom.
+type requestError struct {
+	status    int
+	code      string
+	msg       string
+	retryable bool
+}
+
+func (e *requestError) Error() string {
+	return e.msg
+}
+
+// NotFound is the error of a request for something the server does not know of.
+func NotFound(format string, args ...any) error {
+	return &requestError{status: http.StatusNotFound, code: "not_found", msg: fmt.Sprintf(format, args...)}
+}
+
+// BadRequest is the error of a malformed request.
+func BadRequest(format string, args ...any) error {
+	return &requestError{status: http.StatusBadRequest, code: "bad_request", msg: fmt.Sprintf(format, args...)}
+}
+
+// Unauthorized is the error of a request without valid credentials.
+func Unauthorized(format string, args ...any) error {
+	return &requestError{status: http.StatusUnauthorized, code: "unauthorized", msg: fmt.Sprintf(format, args...)}
+}
+
+// TooManyRequests is the error of a client exceeding its rate limit.
+func TooManyRequests(format string, args ...any) error {
+	return &requestError{status: http.StatusTooManyRequests, code: "rate_limited", msg: fmt.Sprintf(format, args...), retryable: true}
+}
+
+// FromError returns the HTTP status and the APIError of an error returned by a handler.
+func FromError(err error) (int, pkgtypes.APIError) {
+	var reqErr *requestError
+	var upstreamErr *types.UpstreamError
+	switch {
+	case errors.As(err, &reqErr):
+		return reqErr.status, pkgtypes.APIError{Code: reqErr.code, Message: reqErr.msg, Retryable: reqErr.retryable}
+	case errors.Is(err, types.ErrNotReady):
+		return http.StatusServiceUnavailable, pkgtypes.APIError{Code: "not_ready", Message: err.Error(), Retryable: true}
+	case errors.As(err, &upstreamErr):
+		return http.StatusBadGateway, pkgtypes.APIError{
+			Code:      "upstream_error",
+			Message:   upstreamErr.Err.Error(),
+			Upstream:  upstreamErr.Upstream,
+			Retryable: true,
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, pkgtypes.APIError{Code: "timeout", Message: err.Error(), Retryable: true}
+	default:
+		return http.StatusInternalServerError, pkgtypes.APIError{Code: "internal", Message: err.Error()}
+	}
+}
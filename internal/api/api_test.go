@@ -0,0 +1,117 @@
+package api_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/internal/api"
+	"github.com/terra-money/oracle-feeder-go/internal/types"
+	pkgtypes "github.com/terra-money/oracle-feeder-go/pkg/types"
+)
+
+func TestErrorResponses(t *testing.T) {
+	// GIVEN routes failing in different ways
+	gin.SetMode(gin.TestMode)
+	errs := map[string]error{
+		"/upstream":  fmt.Errorf("alliance protocol: %w", types.NewUpstreamError("grpc.node:443", errors.New("connection refused"))),
+		"/not-ready": fmt.Errorf("alliance protocol is %w", types.ErrNotReady),
+		"/not-found": api.NotFound("no price for FOO"),
+		"/internal":  errors.New("boom"),
+	}
+	var routes []api.Route
+	for path, err := range errs {
+		err := err
+		routes = append(routes, api.Route{
+			Method:  http.MethodGet,
+			Path:    path,
+			Handler: func(c *gin.Context) (any, error) { return nil, err },
+		})
+	}
+	routes = append(routes, api.Route{
+		Method:  http.MethodGet,
+		Path:    "/ok",
+		Handler: func(c *gin.Context) (any, error) { return pkgtypes.PriceOfCoin{Denom: "LUNA", Price: 0.5}, nil },
+	})
+	r := gin.New()
+	api.Register(r.Group("/v1"), routes)
+
+	get := func(path string) (int, pkgtypes.ErrorResponse) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1"+path, nil))
+		var res pkgtypes.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return w.Code, res
+	}
+
+	// WHEN / THEN
+	status, res := get("/upstream")
+	require.Equal(t, http.StatusBadGateway, status)
+	require.Equal(t, pkgtypes.APIError{Code: "upstream_error", Message: "connection refused", Upstream: "grpc.node:443", Retryable: true}, res.Error)
+
+	status, res = get("/not-ready")
+	require.Equal(t, http.StatusServiceUnavailable, status)
+	require.Equal(t, "not_ready", res.Error.Code)
+	require.True(t, res.Error.Retryable)
+
+	status, res = get("/not-found")
+	require.Equal(t, http.StatusNotFound, status)
+	require.Equal(t, pkgtypes.APIError{Code: "not_found", Message: "no price for FOO"}, res.Error)
+
+	status, res = get("/internal")
+	require.Equal(t, http.StatusInternalServerError, status)
+	require.Equal(t, pkgtypes.APIError{Code: "internal", Message: "boom"}, res.Error)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/ok", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"denom":"LUNA","price":0.5}`, w.Body.String())
+}
+
+func TestOpenAPI(t *testing.T) {
+	// GIVEN
+	routes := []api.Route{
+		{Method: http.MethodGet, Path: "/latest/:denom", Summary: "price of a denom", Response: pkgtypes.PriceResponse{}},
+		{Method: http.MethodGet, Path: "/alliance/rebalance", Summary: "redelegations", Response: pkgtypes.MsgAllianceRedelegate{}},
+	}
+
+	// WHEN
+	doc := api.OpenAPI("Price Server", "v1", "/v1", routes)
+
+	// THEN the paths, their parameters and the schemas of the responses are described
+	raw, err := json.Marshal(doc)
+	require.NoError(t, err)
+	var res struct {
+		Paths map[string]map[string]struct {
+			Summary    string `json:"summary"`
+			Parameters []struct {
+				Name string `json:"name"`
+				In   string `json:"in"`
+			} `json:"parameters"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]any `json:"properties"`
+				Required   []string                  `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &res))
+
+	require.Equal(t, "price of a denom", res.Paths["/v1/latest/{denom}"]["get"].Summary)
+	require.Len(t, res.Paths["/v1/latest/{denom}"]["get"].Parameters, 1)
+	require.Equal(t, "denom", res.Paths["/v1/latest/{denom}"]["get"].Parameters[0].Name)
+	require.Contains(t, res.Paths, "/v1/alliance/rebalance")
+
+	schemas := res.Components.Schemas
+	require.Equal(t, "number", schemas["PriceOfCoin"].Properties["price"]["type"])
+	require.NotContains(t, schemas["PriceOfCoin"].Properties, "Timestamp")
+	require.ElementsMatch(t, []string{"denom", "price"}, schemas["PriceOfCoin"].Required)
+	require.Equal(t, "#/components/schemas/Redelegation", schemas["AllianceRedelegate"].Properties["redelegations"]["items"].(map[string]any)["$ref"])
+	require.ElementsMatch(t, []string{"code", "message", "retryable"}, schemas["APIError"].Required)
+}
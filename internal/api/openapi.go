@@ -0,0 +1,195 @@
+package api
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	pkgtypes "github.com/terra-money/oracle-feeder-go/pkg/types"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// OpenAPI returns the OpenAPI 3 document of the routes served under basePath.
+func OpenAPI(title, version, basePath string, routes []Route) map[string]any {
+	g := &schemaGenerator{schemas: map[string]any{}, names: map[reflect.Type]string{}}
+	errorSchema := g.schemaOf(reflect.TypeOf(pkgtypes.ErrorResponse{}))
+
+	paths := map[string]any{}
+	for _, route := range routes {
+		path, params := openAPIPath(basePath + route.Path)
+		operation := map[string]any{
+			"summary": route.Summary,
+			"responses": map[string]any{
+				"200": map[string]any{
+					"description": "OK",
+					"content":     jsonContent(g.schemaOf(reflect.TypeOf(route.Response))),
+				},
+				"default": map[string]any{
+					"description": "Error",
+					"content":     jsonContent(errorSchema),
+				},
+			},
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+		item, ok := paths[path].(map[string]any)
+		if !ok {
+			item = map[string]any{}
+			paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = operation
+	}
+
+	return map[string]any{
+		"openapi":    "3.0.3",
+		"info":       map[string]any{"title": title, "version": version},
+		"paths":      paths,
+		"components": map[string]any{"schemas": g.schemas},
+	}
+}
+
+// OpenAPIHandler serves the document of OpenAPI.
+func OpenAPIHandler(doc map[string]any) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, doc)
+	}
+}
+
+// openAPIPath converts the gin path parameters, e.g. :denom, to {denom}.
+func openAPIPath(path string) (string, []any) {
+	var params []any
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			name := segment[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, map[string]any{
+				"name":     name,
+				"in":       "path",
+				"required": true,
+				"schema":   map[string]any{"type": "string"},
+			})
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+func jsonContent(schema any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// schemaGenerator builds the JSON schemas of Go types the way encoding/json
+// marshals them, the structs being referenced from the components.
+type schemaGenerator struct {
+	schemas map[string]any
+	names   map[reflect.Type]string
+}
+
+func (g *schemaGenerator) schemaOf(t reflect.Type) map[string]any {
+	if t == nil {
+		return map[string]any{}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	// e.g. sdk.Dec and time.Time are marshaled as strings
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + g.structName(t)}
+	default:
+		return map[string]any{}
+	}
+}
+
+// structName registers the schema of a named struct and returns its component name.
+func (g *schemaGenerator) structName(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	// the generic types are named after their parameters, e.g. Cached[...]
+	name = strings.NewReplacer("[", "_", "]", "", "/", "_", "*", "").Replace(name)
+	if _, taken := g.schemas[name]; taken {
+		name = strings.ReplaceAll(t.PkgPath(), "/", "_") + "_" + name
+	}
+	g.names[t] = name
+	g.schemas[name] = map[string]any{} // placeholder for the recursive types
+	g.schemas[name] = g.structSchema(t)
+	return name
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		// the fields of an untagged embedded struct are promoted
+		if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			embedded := g.structSchema(field.Type)
+			for key, value := range embedded["properties"].(map[string]any) {
+				properties[key] = value
+			}
+			if fields, ok := embedded["required"].([]string); ok {
+				required = append(required, fields...)
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = g.schemaOf(field.Type)
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,287 @@
+// Package auth authenticates the clients of the price server with API keys
+// and limits the rate of the requests of each key.
+//
+// A client either sends its key in the KeyHeader header, or signs its
+// requests with HMAC-SHA256: it sends its key ID in KeyIDHeader, the
+// timestamp in unix milliseconds in TimestampHeader, and the base64 HMAC of
+// "<timestamp>\n<method>\n<request uri>\n<body>" keyed by its key in SignatureHeader.
+//
+// A group applies to a route with and without the /v1 prefix, and the gRPC
+// clients send the same headers as metadata, see UnaryServerInterceptor.
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/api"
+)
+
+const (
+	KeyHeader       = "X-API-Key"
+	KeyIDHeader     = "X-API-Key-Id"
+	TimestampHeader = "X-API-Timestamp"
+	SignatureHeader = "X-API-Signature"
+
+	SchemeAPIKey = "api_key"
+	SchemeHMAC   = "hmac"
+
+	// KeyIDContextKey holds the ID of the authenticated key in the gin context.
+	KeyIDContextKey = "key_id"
+
+	// MaxSkew is how far from the server time the timestamp of a signed request can be.
+	MaxSkew = 60 * time.Second
+)
+
+// Key is an API key of a client, the ID being what the logs and the rate limits refer to.
+type Key struct {
+	ID     string
+	Secret string
+}
+
+// ParseKeys parses comma separated "<id>:<secret>" pairs.
+func ParseKeys(value string) ([]Key, error) {
+	var keys []Key
+	ids := make(map[string]bool)
+	for i, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(pair, ":")
+		if !ok || id == "" || secret == "" {
+			// the pair is not quoted, it may be a secret
+			return nil, fmt.Errorf("invalid API key #%d, expected <id>:<secret>", i+1)
+		}
+		if ids[id] {
+			return nil, fmt.Errorf("duplicate API key %s", id)
+		}
+		ids[id] = true
+		keys = append(keys, Key{ID: id, Secret: secret})
+	}
+	return keys, nil
+}
+
+type group struct {
+	prefix string
+	scheme string
+}
+
+// Authenticator checks the credentials of the requests to the protected route groups.
+type Authenticator struct {
+	keys    []Key
+	groups  []group // longest prefix first
+	limiter *limiter
+	now     func() time.Time
+}
+
+func NewAuthenticator(cfg config.AuthConfig, keys []Key) (*Authenticator, error) {
+	var groups []group
+	schemes := make(map[string]string)
+	for prefix, scheme := range cfg.Groups {
+		switch scheme {
+		case "", SchemeAPIKey, SchemeHMAC: // "" opens a route of a protected group
+		default:
+			return nil, fmt.Errorf("unknown scheme %s of %s", scheme, prefix)
+		}
+		prefix = unversioned("/" + strings.Trim(prefix, "/"))
+		if other, ok := schemes[prefix]; ok {
+			if other != scheme {
+				return nil, fmt.Errorf("%s is configured with and without /v1 with different schemes", prefix)
+			}
+			continue
+		}
+		schemes[prefix] = scheme
+		groups = append(groups, group{prefix: prefix, scheme: scheme})
+	}
+	sort.Slice(groups, func(i, j int) bool { return len(groups[i].prefix) > len(groups[j].prefix) })
+	for _, g := range groups {
+		if g.scheme != "" && len(keys) == 0 {
+			return nil, fmt.Errorf("%s requires an API key but none is configured", g.prefix)
+		}
+	}
+	return &Authenticator{
+		keys:    keys,
+		groups:  groups,
+		limiter: newLimiter(cfg.RateLimit, cfg.RateLimits),
+		now:     time.Now,
+	}, nil
+}
+
+// NewAuthenticatorFromEnv reads the keys from PRICE_SERVER_API_KEYS.
+func NewAuthenticatorFromEnv(cfg config.AuthConfig) (*Authenticator, error) {
+	keys, err := ParseKeys(os.Getenv("PRICE_SERVER_API_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+	return NewAuthenticator(cfg, keys)
+}
+
+// Middleware rejects the requests to a protected group with 401 unless they are
+// authenticated, and with 429 when their key exceeds its rate limit.
+func (a *Authenticator) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheme := a.schemeOf(c.Request.URL.Path)
+		if scheme == "" {
+			c.Next()
+			return
+		}
+		keyID, err := a.authenticateRequest(c.Request, scheme)
+		if err != nil {
+			api.WriteError(c, api.Unauthorized("%v", err))
+			return
+		}
+		c.Set(KeyIDContextKey, keyID)
+
+		if wait, ok := a.limiter.allow(keyID, a.now()); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			api.WriteError(c, api.TooManyRequests("rate limit of %s exceeded", keyID))
+			return
+		}
+		c.Next()
+	}
+}
+
+// schemeOf returns the scheme of the longest group prefix of the path, none if no group matches.
+func (a *Authenticator) schemeOf(path string) string {
+	path = unversioned(path)
+	for _, g := range a.groups {
+		if g.prefix == "/" || path == g.prefix || strings.HasPrefix(path, g.prefix+"/") {
+			return g.scheme
+		}
+	}
+	return ""
+}
+
+// unversioned strips the /v1 prefix of a path, /v1 being the versioned form of every route.
+func unversioned(path string) string {
+	if path == "/v1" {
+		return "/"
+	}
+	if strings.HasPrefix(path, "/v1/") {
+		return strings.TrimPrefix(path, "/v1")
+	}
+	return path
+}
+
+// authenticateRequest returns the ID of the key the HTTP request is authenticated with.
+func (a *Authenticator) authenticateRequest(req *http.Request, scheme string) (string, error) {
+	var body []byte
+	if scheme == SchemeHMAC && req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return "", err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return a.authenticate(req.Header.Get, scheme, req.Method, req.URL.RequestURI(), body)
+}
+
+// authenticate returns the ID of the key of the request of method on uri,
+// its credentials being read with header.
+func (a *Authenticator) authenticate(header func(string) string, scheme, method, uri string, body []byte) (string, error) {
+	switch scheme {
+	case SchemeAPIKey:
+		secret := header(KeyHeader)
+		if secret == "" {
+			return "", fmt.Errorf("missing %s header", KeyHeader)
+		}
+		for _, key := range a.keys {
+			if subtle.ConstantTimeCompare([]byte(key.Secret), []byte(secret)) == 1 {
+				return key.ID, nil
+			}
+		}
+		return "", errors.New("invalid API key")
+	default:
+		keyID := header(KeyIDHeader)
+		var secret string
+		for _, key := range a.keys {
+			if key.ID == keyID {
+				secret = key.Secret
+			}
+		}
+		if secret == "" {
+			return "", fmt.Errorf("unknown API key ID %q", keyID)
+		}
+		timestamp, err := strconv.ParseInt(header(TimestampHeader), 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s header", TimestampHeader)
+		}
+		if skew := a.now().Sub(time.UnixMilli(timestamp)); skew > MaxSkew || skew < -MaxSkew {
+			return "", fmt.Errorf("timestamp is %s off the server time", skew.Round(time.Second))
+		}
+		signature, err := base64.StdEncoding.DecodeString(header(SignatureHeader))
+		if err != nil || !hmac.Equal(signature, sign(secret, timestamp, method, uri, body)) {
+			return "", errors.New("invalid signature")
+		}
+		return keyID, nil
+	}
+}
+
+func sign(secret string, timestamp int64, method, uri string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d\n%s\n%s\n", timestamp, method, uri)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// LogFormatter is the access log format of gin with the ID of the key of each request.
+func LogFormatter(param gin.LogFormatterParams) string {
+	keyID, _ := param.Keys[KeyIDContextKey].(string)
+	if keyID == "" {
+		keyID = "-"
+	}
+	return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v | key %s\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		param.StatusCode,
+		param.Latency,
+		param.ClientIP,
+		param.Method,
+		param.Path,
+		keyID,
+		param.ErrorMessage,
+	)
+}
+
+// Credentials authenticate the requests of a client of the price server, e.g. the feeder.
+type Credentials struct {
+	KeyID string // sign the requests with HMAC when set, send the key as is otherwise
+	Key   string
+}
+
+// CredentialsFromEnv reads PRICE_SERVER_API_KEY and PRICE_SERVER_API_KEY_ID,
+// it returns nil when no key is set.
+func CredentialsFromEnv() *Credentials {
+	key := os.Getenv("PRICE_SERVER_API_KEY")
+	if key == "" {
+		return nil
+	}
+	return &Credentials{KeyID: os.Getenv("PRICE_SERVER_API_KEY_ID"), Key: key}
+}
+
+// Authorize sets the headers authenticating the request with the body.
+func (c *Credentials) Authorize(req *http.Request, body []byte) {
+	if c.KeyID == "" {
+		req.Header.Set(KeyHeader, c.Key)
+		return
+	}
+	timestamp := time.Now().UnixMilli()
+	req.Header.Set(KeyIDHeader, c.KeyID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, base64.StdEncoding.EncodeToString(sign(c.Key, timestamp, req.Method, req.URL.RequestURI(), body)))
+}
@@ -0,0 +1,135 @@
+package auth_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/auth"
+	pkgtypes "github.com/terra-money/oracle-feeder-go/pkg/types"
+)
+
+func newRouter(t *testing.T, cfg config.AuthConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	keys, err := auth.ParseKeys("feeder:feeder-secret, team-b:team-b-secret")
+	require.NoError(t, err)
+	authenticator, err := auth.NewAuthenticator(cfg, keys)
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(authenticator.Middleware())
+	handler := func(c *gin.Context) { c.String(http.StatusOK, c.GetString(auth.KeyIDContextKey)) }
+	r.GET("/ready", handler)
+	r.GET("/latest", handler)
+	r.GET("/v1/latest", handler)
+	r.GET("/alliance/rebalance", handler)
+	r.GET("/v1/alliance/rebalance", handler)
+	r.GET("/v1/alliance/protocol", handler)
+	return r
+}
+
+func serve(r *gin.Engine, path string, credentials *auth.Credentials) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if credentials != nil {
+		credentials.Authorize(req, nil)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthenticatorSchemes(t *testing.T) {
+	// GIVEN groups applying with and without /v1
+	r := newRouter(t, config.AuthConfig{Groups: map[string]string{
+		"/latest":            auth.SchemeAPIKey,
+		"/v1/alliance":       auth.SchemeHMAC,
+		"/alliance/protocol": "",
+	}})
+
+	// WHEN / THEN the routes out of the groups are open
+	w := serve(r, "/ready", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Body.String())
+	require.Equal(t, http.StatusOK, serve(r, "/v1/alliance/protocol", nil).Code)
+
+	// the API key identifies the client
+	for _, path := range []string{"/latest", "/v1/latest"} {
+		w = serve(r, path, &auth.Credentials{Key: "team-b-secret"})
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "team-b", w.Body.String())
+		require.Equal(t, http.StatusUnauthorized, serve(r, path, nil).Code)
+	}
+	w = serve(r, "/latest", &auth.Credentials{Key: "wrong"})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	var res pkgtypes.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	require.Equal(t, pkgtypes.APIError{Code: "unauthorized", Message: "invalid API key"}, res.Error)
+
+	// the HMAC group does not accept the key as is
+	for _, path := range []string{"/alliance/rebalance", "/v1/alliance/rebalance"} {
+		w = serve(r, path, &auth.Credentials{KeyID: "feeder", Key: "feeder-secret"})
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "feeder", w.Body.String())
+		require.Equal(t, http.StatusUnauthorized, serve(r, path, &auth.Credentials{Key: "feeder-secret"}).Code)
+		require.Equal(t, http.StatusUnauthorized, serve(r, path, &auth.Credentials{KeyID: "feeder", Key: "team-b-secret"}).Code)
+	}
+
+	// nor a signature older than the maximum skew
+	req := httptest.NewRequest(http.MethodGet, "/v1/alliance/rebalance", nil)
+	(&auth.Credentials{KeyID: "feeder", Key: "feeder-secret"}).Authorize(req, nil)
+	req.Header.Set(auth.TimestampHeader, strconv.FormatInt(time.Now().Add(-2*auth.MaxSkew).UnixMilli(), 10))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthenticatorVersionedGroups(t *testing.T) {
+	// GIVEN the same group with and without /v1
+	groups := map[string]string{"/alliance": auth.SchemeAPIKey, "/v1/alliance": auth.SchemeHMAC}
+
+	// WHEN
+	_, err := auth.NewAuthenticator(config.AuthConfig{Groups: groups}, []auth.Key{{ID: "feeder", Secret: "secret"}})
+
+	// THEN
+	require.EqualError(t, err, "/alliance is configured with and without /v1 with different schemes")
+}
+
+func TestAuthenticatorRateLimit(t *testing.T) {
+	// GIVEN a burst of 2 requests for the feeder and of 1 for the other keys
+	r := newRouter(t, config.AuthConfig{
+		Groups:     map[string]string{"/": auth.SchemeAPIKey},
+		RateLimit:  config.RateLimitConfig{Rate: 0.1, Burst: 1},
+		RateLimits: map[string]config.RateLimitConfig{"feeder": {Rate: 0.1, Burst: 2}},
+	})
+	feeder := &auth.Credentials{Key: "feeder-secret"}
+	teamB := &auth.Credentials{Key: "team-b-secret"}
+
+	// WHEN / THEN each key has its own bucket
+	require.Equal(t, http.StatusOK, serve(r, "/latest", feeder).Code)
+	require.Equal(t, http.StatusOK, serve(r, "/latest", teamB).Code)
+	require.Equal(t, http.StatusOK, serve(r, "/latest", feeder).Code)
+	w := serve(r, "/latest", feeder)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "10", w.Header().Get("Retry-After"))
+	var res pkgtypes.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	require.Equal(t, "rate_limited", res.Error.Code)
+	require.True(t, res.Error.Retryable)
+	require.Equal(t, http.StatusTooManyRequests, serve(r, "/latest", teamB).Code)
+}
+
+func TestParseKeys(t *testing.T) {
+	_, err := auth.ParseKeys("feeder:secret,not-a-pair")
+	require.EqualError(t, err, "invalid API key #2, expected <id>:<secret>")
+	_, err = auth.ParseKeys("feeder:a,feeder:b")
+	require.Error(t, err)
+	keys, err := auth.ParseKeys("")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
@@ -0,0 +1,95 @@
+package auth_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	checkMethod = "/grpc.health.v1.Health/Check"
+	watchMethod = "/grpc.health.v1.Health/Watch"
+)
+
+func newHealthClient(t *testing.T, cfg config.AuthConfig) healthpb.HealthClient {
+	keys, err := auth.ParseKeys("feeder:feeder-secret, team-b:team-b-secret")
+	require.NoError(t, err)
+	authenticator, err := auth.NewAuthenticator(cfg, keys)
+	require.NoError(t, err)
+
+	// the health methods stand for the alliance routes
+	routes := map[string]string{checkMethod: "/alliance/rebalance", watchMethod: "/alliance/rebalance"}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(authenticator.UnaryServerInterceptor(routes)),
+		grpc.StreamInterceptor(authenticator.StreamServerInterceptor(routes)),
+	)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestGRPCSchemes(t *testing.T) {
+	// GIVEN the versioned alliance group signed with HMAC
+	client := newHealthClient(t, config.AuthConfig{Groups: map[string]string{"/v1/alliance": auth.SchemeHMAC}})
+	feeder := &auth.Credentials{KeyID: "feeder", Key: "feeder-secret"}
+	ctx := context.Background()
+
+	// WHEN / THEN the requests are authenticated with the same keys as the HTTP routes
+	_, err := client.Check(feeder.AuthorizeGRPC(ctx, checkMethod), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.Check((&auth.Credentials{Key: "feeder-secret"}).AuthorizeGRPC(ctx, checkMethod), &healthpb.HealthCheckRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	// a signature is only valid for its method
+	_, err = client.Check(feeder.AuthorizeGRPC(ctx, watchMethod), &healthpb.HealthCheckRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// and so are the streams
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	stream, err = client.Watch(feeder.AuthorizeGRPC(ctx, watchMethod), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	res, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+}
+
+func TestGRPCRateLimit(t *testing.T) {
+	// GIVEN a burst of 1 request per key
+	client := newHealthClient(t, config.AuthConfig{
+		Groups:    map[string]string{"/": auth.SchemeAPIKey},
+		RateLimit: config.RateLimitConfig{Rate: 0.1, Burst: 1},
+	})
+	ctx := (&auth.Credentials{Key: "feeder-secret"}).AuthorizeGRPC(context.Background(), checkMethod)
+
+	// WHEN
+	_, first := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	var header metadata.MD
+	_, second := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Header(&header))
+
+	// THEN
+	require.NoError(t, first)
+	require.Equal(t, codes.ResourceExhausted, status.Code(second))
+	require.Equal(t, []string{"10"}, header.Get("retry-after"))
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/terra-money/oracle-feeder-go/config"
+)
+
+// bucket holds the tokens of a key, a request taking one.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limiter is a token bucket per key, refilled at the rate of the key up to its burst.
+type limiter struct {
+	rateLimit  config.RateLimitConfig
+	rateLimits map[string]config.RateLimitConfig
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func newLimiter(rateLimit config.RateLimitConfig, rateLimits map[string]config.RateLimitConfig) *limiter {
+	return &limiter{rateLimit: rateLimit, rateLimits: rateLimits, buckets: make(map[string]*bucket)}
+}
+
+// allow takes a token of the key, or returns how long until one is available.
+func (l *limiter) allow(keyID string, now time.Time) (time.Duration, bool) {
+	rateLimit, ok := l.rateLimits[keyID]
+	if !ok {
+		rateLimit = l.rateLimit
+	}
+	if rateLimit.Rate <= 0 {
+		return 0, true
+	}
+	burst := float64(rateLimit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[keyID]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[keyID] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * rateLimit.Rate
+	if b.tokens > burst {
+		b.tokens = burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / rateLimit.Rate * float64(time.Second)), false
+	}
+	b.tokens--
+	return 0, true
+}
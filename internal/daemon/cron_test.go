@@ -0,0 +1,41 @@
+package daemon_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/internal/daemon"
+)
+
+func TestCronNext(t *testing.T) {
+	from := time.Date(2023, 9, 1, 10, 17, 30, 0, time.UTC) // a friday
+	for expr, next := range map[string]time.Time{
+		"* * * * *":         time.Date(2023, 9, 1, 10, 18, 0, 0, time.UTC),
+		"*/15 * * * *":      time.Date(2023, 9, 1, 10, 30, 0, 0, time.UTC),
+		"0 */6 * * *":       time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC),
+		"30 9 * * *":        time.Date(2023, 9, 2, 9, 30, 0, 0, time.UTC),
+		"0 12 * * 1-5":      time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC),
+		"0 12 * * 0":        time.Date(2023, 9, 3, 12, 0, 0, 0, time.UTC),
+		"0 12 * * 7":        time.Date(2023, 9, 3, 12, 0, 0, 0, time.UTC),
+		"0 0 1 1,7 *":       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"0 0 29 2 *":        time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		"0 0 15 * 1":        time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC), // the monday comes before the 15th
+		"5,10-12/2 8 * * *": time.Date(2023, 9, 2, 8, 5, 0, 0, time.UTC),
+	} {
+		cron, err := daemon.ParseCron(expr)
+		require.NoError(t, err, expr)
+		require.Equal(t, next, cron.Next(from), expr)
+	}
+
+	cron, err := daemon.ParseCron("5,10-12/2 8 * * *")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2023, 9, 2, 8, 12, 0, 0, time.UTC), cron.Next(time.Date(2023, 9, 2, 8, 10, 0, 0, time.UTC)))
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{"* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := daemon.ParseCron(expr)
+		require.Error(t, err, expr)
+	}
+}
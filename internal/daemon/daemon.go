@@ -0,0 +1,192 @@
+// Package daemon runs jobs on their schedules in a single process, retrying
+// the failed runs and never running two jobs of the same lock at once.
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/terra-money/oracle-feeder-go/internal/reporting"
+)
+
+// Schedule returns the time of the run following t.
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// Every is the schedule of a job run on an interval, starting with the daemon.
+type Every time.Duration
+
+func (e Every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// RetryPolicy retries a failed run up to Retries times, waiting Delay
+// before the first retry and twice as long before each next one, up to MaxDelay.
+type RetryPolicy struct {
+	Retries  int
+	Delay    time.Duration
+	MaxDelay time.Duration
+}
+
+// Job is a task run on a schedule.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	Jitter   time.Duration // random delay added to each run so that instances do not run in lockstep
+	Retry    RetryPolicy
+	// jobs of the same lock never run at once, e.g. the jobs signing
+	// transactions with the same key which would reuse its sequence
+	Lock string
+	Run  func(ctx context.Context) error
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks an error that retrying would not fix.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// IsPermanent tells whether the error was marked by Permanent.
+func IsPermanent(err error) bool {
+	var permanent permanentError
+	return errors.As(err, &permanent)
+}
+
+// Daemon runs the jobs until its context is done.
+type Daemon struct {
+	jobs  []Job
+	locks map[string]*sync.Mutex
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) bool
+}
+
+func New(jobs []Job) (*Daemon, error) {
+	locks := make(map[string]*sync.Mutex)
+	names := make(map[string]bool)
+	for _, job := range jobs {
+		if job.Schedule == nil {
+			return nil, fmt.Errorf("job %s has no schedule", job.Name)
+		}
+		if names[job.Name] {
+			return nil, fmt.Errorf("duplicate job %s", job.Name)
+		}
+		names[job.Name] = true
+		if job.Lock != "" && locks[job.Lock] == nil {
+			locks[job.Lock] = &sync.Mutex{}
+		}
+	}
+	return &Daemon{jobs: jobs, locks: locks, now: time.Now, sleep: sleep}, nil
+}
+
+// Run schedules every job until ctx is done, then waits for the running ones to complete.
+func (d *Daemon) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range d.jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer reporting.Recover()
+			defer wg.Done()
+			d.schedule(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+}
+
+// schedule runs the job at each time of its schedule, a run that
+// lasts past the next time delays it rather than overlapping.
+func (d *Daemon) schedule(ctx context.Context, job Job) {
+	// the jobs run on an interval run right away, like the providers fetch on start
+	_, first := job.Schedule.(Every)
+	for {
+		next := job.Schedule.Next(d.now())
+		if first {
+			next, first = d.now(), false
+		}
+		if next.IsZero() {
+			log.Printf("job %s: no next run", job.Name)
+			return
+		}
+		if job.Jitter > 0 {
+			next = next.Add(time.Duration(rand.Int63n(int64(job.Jitter))))
+		}
+		log.Printf("job %s: next run at %s", job.Name, next.UTC().Format(time.RFC3339))
+		if !d.sleep(ctx, next.Sub(d.now())) {
+			return
+		}
+		// the run is not cancelled with the daemon, a transaction may be on its way
+		if err := d.runWithRetries(ctx, job); err != nil {
+			log.Printf("job %s failed: %v", job.Name, err)
+		}
+	}
+}
+
+// runWithRetries runs the job until it succeeds or its retries are exhausted,
+// the lock being released while waiting for the next attempt.
+func (d *Daemon) runWithRetries(ctx context.Context, job Job) error {
+	delay := job.Retry.Delay
+	for attempt := 0; ; attempt++ {
+		err := d.runLocked(job)
+		if err == nil {
+			return nil
+		}
+		if IsPermanent(err) {
+			return err
+		}
+		if attempt >= job.Retry.Retries {
+			return fmt.Errorf("all %d attempts failed, last error: %w", attempt+1, err)
+		}
+		log.Printf("job %s: attempt %d failed, retrying in %s: %v", job.Name, attempt+1, delay, err)
+		if !d.sleep(ctx, delay) {
+			return err
+		}
+		delay *= 2
+		if job.Retry.MaxDelay > 0 && delay > job.Retry.MaxDelay {
+			delay = job.Retry.MaxDelay
+		}
+	}
+}
+
+func (d *Daemon) runLocked(job Job) (err error) {
+	if lock := d.locks[job.Lock]; lock != nil {
+		lock.Lock()
+		defer lock.Unlock()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(context.Background())
+}
+
+// sleep waits for the duration, it returns false when ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,114 @@
+package daemon_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/internal/daemon"
+)
+
+func TestDaemonLocks(t *testing.T) {
+	// GIVEN two slow jobs signing with the same key and one with another key
+	var mu sync.Mutex
+	var running, maxRunning int
+	var otherKeyRuns atomic.Int32
+	slowJob := func(ctx context.Context) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	}
+	d, err := daemon.New([]daemon.Job{
+		{Name: "oracle", Schedule: daemon.Every(time.Millisecond), Lock: "terra1key", Run: slowJob},
+		{Name: "rebalance", Schedule: daemon.Every(time.Millisecond), Lock: "terra1key", Run: slowJob},
+		{Name: "other", Schedule: daemon.Every(time.Millisecond), Lock: "terra1other", Run: func(ctx context.Context) error {
+			otherKeyRuns.Add(1)
+			return nil
+		}},
+	})
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	// WHEN
+	d.Run(ctx)
+
+	// THEN the jobs of the key never overlap while the other one is not held back
+	require.Equal(t, 1, maxRunning)
+	require.Greater(t, otherKeyRuns.Load(), int32(20))
+}
+
+func TestDaemonRetries(t *testing.T) {
+	// GIVEN a job failing twice, and one failing permanently
+	var calls, permanentCalls atomic.Int32
+	var mu sync.Mutex
+	var succeeded bool
+	d, err := daemon.New([]daemon.Job{
+		{
+			Name:     "flaky",
+			Schedule: daemon.Every(time.Hour),
+			Retry:    daemon.RetryPolicy{Retries: 3, Delay: time.Millisecond},
+			Run: func(ctx context.Context) error {
+				if calls.Add(1) <= 2 {
+					return errors.New("node unavailable")
+				}
+				mu.Lock()
+				succeeded = true
+				mu.Unlock()
+				return nil
+			},
+		},
+		{
+			Name:     "permanent",
+			Schedule: daemon.Every(time.Hour),
+			Retry:    daemon.RetryPolicy{Retries: 3, Delay: time.Millisecond},
+			Run: func(ctx context.Context) error {
+				permanentCalls.Add(1)
+				return daemon.Permanent(errors.New("unknown route"))
+			},
+		},
+	})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// WHEN the jobs run on start
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return succeeded
+	}, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	// THEN
+	require.Equal(t, int32(3), calls.Load())
+	require.Equal(t, int32(1), permanentCalls.Load())
+}
+
+func TestNewDaemonErrors(t *testing.T) {
+	run := func(ctx context.Context) error { return nil }
+	_, err := daemon.New([]daemon.Job{{Name: "oracle", Run: run}})
+	require.Error(t, err)
+	_, err = daemon.New([]daemon.Job{
+		{Name: "oracle", Schedule: daemon.Every(time.Second), Run: run},
+		{Name: "oracle", Schedule: daemon.Every(time.Second), Run: run},
+	})
+	require.Error(t, err)
+}
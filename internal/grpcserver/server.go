@@ -0,0 +1,208 @@
+// Package grpcserver serves the prices and the alliance data of the price
+// server over gRPC, see proto/priceserver/v1/query.proto.
+package grpcserver
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	alliance_provider "github.com/terra-money/oracle-feeder-go/internal/provider/alliance"
+	"github.com/terra-money/oracle-feeder-go/pkg/priceserver"
+	pkgtypes "github.com/terra-money/oracle-feeder-go/pkg/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultStreamInterval is the interval of StreamPrices when the request has none.
+const defaultStreamInterval = 10 * time.Second
+
+// PriceProvider is implemented by the provider manager.
+type PriceProvider interface {
+	GetPrices(ctx context.Context) *pkgtypes.PricesResponse
+	GetPrice(ctx context.Context, denom string) *pkgtypes.PriceResponse
+	Subscribe(ctx context.Context) <-chan *pkgtypes.PricesResponse
+}
+
+// AllianceProvider is implemented by the alliance provider.
+type AllianceProvider interface {
+	GetProtocolsInfo(ctx context.Context) (*alliance_provider.Cached[pkgtypes.MsgUpdateChainsInfo], error)
+	GetAllianceRedelegateReq(ctx context.Context) (*alliance_provider.Cached[pkgtypes.MsgAllianceRedelegate], error)
+	GetAllianceInitialDelegations(ctx context.Context) (*alliance_provider.Cached[pkgtypes.MsgAllianceDelegations], error)
+}
+
+// Routes maps the methods of the PriceServer service to the paths of their HTTP
+// routes, for the route groups of the authenticator to apply to both.
+var Routes = map[string]string{
+	"/priceserver.v1.PriceServer/GetPrices":        "/latest",
+	"/priceserver.v1.PriceServer/GetPrice":         "/latest",
+	"/priceserver.v1.PriceServer/StreamPrices":     "/stream",
+	"/priceserver.v1.PriceServer/GetProtocolsInfo": "/alliance/protocol",
+	"/priceserver.v1.PriceServer/GetRedelegations": "/alliance/rebalance",
+	"/priceserver.v1.PriceServer/GetDelegations":   "/alliance/delegations",
+}
+
+type server struct {
+	prices   PriceProvider
+	alliance AllianceProvider
+}
+
+// NewServer returns a gRPC server with the PriceServer service registered.
+func NewServer(prices PriceProvider, alliance AllianceProvider, opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(opts...)
+	priceserver.RegisterPriceServerServer(s, &server{prices: prices, alliance: alliance})
+	return s
+}
+
+func (s *server) GetPrices(ctx context.Context, _ *priceserver.GetPricesRequest) (*priceserver.GetPricesResponse, error) {
+	return toPricesResponse(s.prices.GetPrices(ctx), nil), nil
+}
+
+func (s *server) GetPrice(ctx context.Context, req *priceserver.GetPriceRequest) (*priceserver.GetPriceResponse, error) {
+	res := s.prices.GetPrice(ctx, req.Denom)
+	if res == nil {
+		return nil, status.Errorf(codes.NotFound, "no price for %s", req.Denom)
+	}
+	return &priceserver.GetPriceResponse{
+		CreatedAt: res.Timestamp,
+		Price:     toPriceOfCoin(res.Price),
+	}, nil
+}
+
+// StreamPrices sends the prices the manager publishes to its subscribers,
+// at most one response every interval.
+func (s *server) StreamPrices(req *priceserver.StreamPricesRequest, stream priceserver.PriceServer_StreamPricesServer) error {
+	interval := defaultStreamInterval
+	if req.Interval > 0 {
+		interval = time.Duration(req.Interval) * time.Second
+	}
+	denoms := make(map[string]bool)
+	for _, denom := range req.Denoms {
+		denoms[strings.ToUpper(denom)] = true
+	}
+
+	var lastSent time.Time
+	for res := range s.prices.Subscribe(stream.Context()) {
+		if time.Since(lastSent) < interval {
+			continue
+		}
+		lastSent = time.Now()
+		if err := stream.Send(toPricesResponse(res, denoms)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *server) GetProtocolsInfo(ctx context.Context, _ *priceserver.GetProtocolsInfoRequest) (*priceserver.GetProtocolsInfoResponse, error) {
+	res, err := s.alliance.GetProtocolsInfo(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	chainsInfo := res.Value.UpdateChainsInfo.ChainsInfo
+	protocolsInfo := make([]*priceserver.ProtocolInfo, 0, len(chainsInfo.ProtocolsInfo))
+	for _, info := range chainsInfo.ProtocolsInfo {
+		protocolInfo := &priceserver.ProtocolInfo{
+			ChainId: info.ChainId,
+			NativeToken: &priceserver.NativeToken{
+				Denom:            info.NativeToken.Denom,
+				TokenPrice:       decString(info.NativeToken.TokenPrice),
+				AnnualProvisions: decString(info.NativeToken.AnnualProvisions),
+			},
+		}
+		for _, alliance := range info.LunaAlliances {
+			protocolInfo.LunaAlliances = append(protocolInfo.LunaAlliances, &priceserver.LunaAlliance{
+				IbcDenom:               alliance.IBCDenom,
+				RebaseFactor:           decString(alliance.RebaseFactor),
+				NormalizedRewardWeight: decString(alliance.NormalizedRewardWeight),
+				AnnualTakeRate:         decString(alliance.AnnualTakeRate),
+				TotalLsdStaked:         decString(alliance.TotalLSDStaked),
+			})
+		}
+		for _, alliance := range info.ChainAlliancesOnPhoenix {
+			protocolInfo.ChainAlliancesOnPhoenix = append(protocolInfo.ChainAlliancesOnPhoenix, &priceserver.BaseAlliance{
+				IbcDenom:     alliance.IBCDenom,
+				RebaseFactor: decString(alliance.RebaseFactor),
+			})
+		}
+		protocolsInfo = append(protocolsInfo, protocolInfo)
+	}
+	return &priceserver.GetProtocolsInfoResponse{
+		LunaPrice:     decString(chainsInfo.LunaPrice),
+		ProtocolsInfo: protocolsInfo,
+		AsOf:          res.AsOf.UTC().Format(time.RFC3339),
+		Stale:         res.Stale,
+	}, nil
+}
+
+func (s *server) GetRedelegations(ctx context.Context, _ *priceserver.GetRedelegationsRequest) (*priceserver.GetRedelegationsResponse, error) {
+	res, err := s.alliance.GetAllianceRedelegateReq(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	redelegations := make([]*priceserver.Redelegation, 0, len(res.Value.AllianceRedelegate.Redelegations))
+	for _, redelegation := range res.Value.AllianceRedelegate.Redelegations {
+		redelegations = append(redelegations, &priceserver.Redelegation{
+			SrcValidator: redelegation.SrcValidator,
+			DstValidator: redelegation.DstValidator,
+			Amount:       redelegation.Amount,
+		})
+	}
+	return &priceserver.GetRedelegationsResponse{
+		Redelegations: redelegations,
+		AsOf:          res.AsOf.UTC().Format(time.RFC3339),
+		Stale:         res.Stale,
+	}, nil
+}
+
+func (s *server) GetDelegations(ctx context.Context, _ *priceserver.GetDelegationsRequest) (*priceserver.GetDelegationsResponse, error) {
+	res, err := s.alliance.GetAllianceInitialDelegations(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	delegations := make([]*priceserver.Delegation, 0, len(res.Value.AllianceDelegations.AllianceDelegations))
+	for _, delegation := range res.Value.AllianceDelegations.AllianceDelegations {
+		delegations = append(delegations, &priceserver.Delegation{
+			Validator: delegation.Validator,
+			Amount:    delegation.Amount,
+		})
+	}
+	return &priceserver.GetDelegationsResponse{
+		Delegations: delegations,
+		AsOf:        res.AsOf.UTC().Format(time.RFC3339),
+		Stale:       res.Stale,
+	}, nil
+}
+
+// toPricesResponse keeps the prices of the denoms, or all of them when denoms is empty.
+func toPricesResponse(res *pkgtypes.PricesResponse, denoms map[string]bool) *priceserver.GetPricesResponse {
+	prices := make([]*priceserver.PriceOfCoin, 0, len(res.Prices))
+	for _, price := range res.Prices {
+		if len(denoms) > 0 && !denoms[strings.ToUpper(price.Denom)] {
+			continue
+		}
+		prices = append(prices, toPriceOfCoin(price))
+	}
+	return &priceserver.GetPricesResponse{
+		CreatedAt: res.Timestamp,
+		Prices:    prices,
+	}
+}
+
+func toPriceOfCoin(price pkgtypes.PriceOfCoin) *priceserver.PriceOfCoin {
+	return &priceserver.PriceOfCoin{
+		Denom:     price.Denom,
+		Price:     price.Price,
+		Deviation: price.Deviation,
+		Divergent: price.Divergent,
+	}
+}
+
+func decString(dec sdk.Dec) string {
+	if dec.IsNil() {
+		return ""
+	}
+	return dec.String()
+}
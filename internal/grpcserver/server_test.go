@@ -0,0 +1,168 @@
+package grpcserver_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/internal/grpcserver"
+	alliance_provider "github.com/terra-money/oracle-feeder-go/internal/provider/alliance"
+	internal_types "github.com/terra-money/oracle-feeder-go/internal/types"
+	"github.com/terra-money/oracle-feeder-go/pkg/priceserver"
+	pkgtypes "github.com/terra-money/oracle-feeder-go/pkg/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+type prices struct{}
+
+func (prices) GetPrices(ctx context.Context) *pkgtypes.PricesResponse {
+	return &pkgtypes.PricesResponse{
+		Timestamp: "2023-09-01T00:00:00Z",
+		Prices: []pkgtypes.PriceOfCoin{
+			{Denom: "LUNA", Price: 0.5, Deviation: 0.01},
+			{Denom: "BTC", Price: 26000},
+		},
+	}
+}
+
+func (p prices) GetPrice(ctx context.Context, denom string) *pkgtypes.PriceResponse {
+	if denom != "LUNA" {
+		return nil
+	}
+	return &pkgtypes.PriceResponse{Timestamp: "2023-09-01T00:00:00Z", Price: p.GetPrices(ctx).Prices[0]}
+}
+
+// Subscribe publishes the prices every 100ms, timestamped with their sequence number.
+func (p prices) Subscribe(ctx context.Context) <-chan *pkgtypes.PricesResponse {
+	ch := make(chan *pkgtypes.PricesResponse, 1)
+	go func() {
+		defer close(ch)
+		for i := 0; ; i++ {
+			res := p.GetPrices(ctx)
+			res.Timestamp = fmt.Sprintf("2023-09-01T00:00:%02dZ", i)
+			select {
+			case ch <- res:
+			case <-ctx.Done():
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}()
+	return ch
+}
+
+type alliance struct{}
+
+var asOf = time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+
+func (alliance) GetProtocolsInfo(ctx context.Context) (*alliance_provider.Cached[pkgtypes.MsgUpdateChainsInfo], error) {
+	res := pkgtypes.NewMsgUpdateChainsInfo(internal_types.AllianceProtocolRes{
+		LunaPrice: sdk.MustNewDecFromStr("0.5"),
+		ProtocolsInfo: []internal_types.ProtocolInfo{internal_types.NewProtocolInfo(
+			"carbon-1",
+			internal_types.NewNativeToken("swth", sdk.MustNewDecFromStr("0.004"), sdk.NewDec(1000)),
+			[]internal_types.LunaAlliance{internal_types.NewLunaAlliance("ibc/LUNA", sdk.OneDec(), sdk.ZeroDec(), sdk.NewDec(10), sdk.OneDec())},
+			[]internal_types.BaseAlliance{internal_types.NewBaseAlliance("ibc/SWTH", sdk.Dec{})},
+		)},
+	})
+	return &alliance_provider.Cached[pkgtypes.MsgUpdateChainsInfo]{Value: res, AsOf: asOf, Stale: true}, nil
+}
+
+func (alliance) GetAllianceRedelegateReq(ctx context.Context) (*alliance_provider.Cached[pkgtypes.MsgAllianceRedelegate], error) {
+	res := pkgtypes.NewMsgAllianceRedelegate([]internal_types.Redelegation{internal_types.NewRedelegation("terravaloper1a", "terravaloper1b", "100")})
+	return &alliance_provider.Cached[pkgtypes.MsgAllianceRedelegate]{Value: res, AsOf: asOf}, nil
+}
+
+func (alliance) GetAllianceInitialDelegations(ctx context.Context) (*alliance_provider.Cached[pkgtypes.MsgAllianceDelegations], error) {
+	return nil, errors.New("node unavailable")
+}
+
+func newClient(t *testing.T) priceserver.PriceServerClient {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpcserver.NewServer(prices{}, alliance{})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return priceserver.NewPriceServerClient(conn)
+}
+
+func TestPrices(t *testing.T) {
+	// GIVEN
+	client := newClient(t)
+	ctx := context.Background()
+
+	// WHEN
+	all, err := client.GetPrices(ctx, &priceserver.GetPricesRequest{})
+	require.NoError(t, err)
+	luna, err := client.GetPrice(ctx, &priceserver.GetPriceRequest{Denom: "LUNA"})
+	require.NoError(t, err)
+	_, err = client.GetPrice(ctx, &priceserver.GetPriceRequest{Denom: "ATOM"})
+
+	// THEN
+	require.Len(t, all.Prices, 2)
+	require.Equal(t, "2023-09-01T00:00:00Z", all.CreatedAt)
+	require.Equal(t, &priceserver.PriceOfCoin{Denom: "LUNA", Price: 0.5, Deviation: 0.01}, luna.Price)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestStreamPrices(t *testing.T) {
+	// GIVEN a stream of the LUNA price only
+	client := newClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.StreamPrices(ctx, &priceserver.StreamPricesRequest{Interval: 1, Denoms: []string{"luna"}})
+	require.NoError(t, err)
+
+	// WHEN
+	var timestamps []string
+	for i := 0; i < 2; i++ {
+		res, err := stream.Recv()
+
+		// THEN the published prices are sent once a second
+		require.NoError(t, err)
+		require.Len(t, res.Prices, 1)
+		require.Equal(t, "LUNA", res.Prices[0].Denom)
+		timestamps = append(timestamps, res.CreatedAt)
+	}
+	require.Equal(t, "2023-09-01T00:00:00Z", timestamps[0])
+	// the prices published in the meantime are skipped
+	require.Greater(t, timestamps[1], "2023-09-01T00:00:09Z")
+}
+
+func TestAlliance(t *testing.T) {
+	// GIVEN
+	client := newClient(t)
+	ctx := context.Background()
+
+	// WHEN
+	protocols, err := client.GetProtocolsInfo(ctx, &priceserver.GetProtocolsInfoRequest{})
+	require.NoError(t, err)
+	redelegations, err := client.GetRedelegations(ctx, &priceserver.GetRedelegationsRequest{})
+	require.NoError(t, err)
+	_, err = client.GetDelegations(ctx, &priceserver.GetDelegationsRequest{})
+
+	// THEN the decimals are formatted as strings
+	require.Equal(t, "2023-09-01T00:00:00Z", protocols.AsOf)
+	require.True(t, protocols.Stale)
+	require.False(t, redelegations.Stale)
+	require.Equal(t, "0.500000000000000000", protocols.LunaPrice)
+	require.Len(t, protocols.ProtocolsInfo, 1)
+	require.Equal(t, "carbon-1", protocols.ProtocolsInfo[0].ChainId)
+	require.Equal(t, "0.004000000000000000", protocols.ProtocolsInfo[0].NativeToken.TokenPrice)
+	require.Equal(t, "10.000000000000000000", protocols.ProtocolsInfo[0].LunaAlliances[0].TotalLsdStaked)
+	require.Empty(t, protocols.ProtocolsInfo[0].ChainAlliancesOnPhoenix[0].RebaseFactor)
+	require.Equal(t, []*priceserver.Redelegation{{SrcValidator: "terravaloper1a", DstValidator: "terravaloper1b", Amount: "100"}}, redelegations.Redelegations)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
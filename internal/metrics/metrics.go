@@ -0,0 +1,180 @@
+// Package metrics exposes the health of the price server to Prometheus.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+)
+
+const namespace = "price_server"
+
+// Registry holds every metric of the price server.
+var Registry = prometheus.NewRegistry()
+
+var (
+	providerMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "provider_messages_total",
+		Help:      "Prices received from each provider, a websocket message or a successful poll.",
+	}, []string{"provider"})
+	providerErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "provider_errors_total",
+		Help:      "Failed polls and malformed messages of each provider.",
+	}, []string{"provider"})
+	providerReconnects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "provider_reconnects_total",
+		Help:      "Websocket reconnections of each provider.",
+	}, []string{"provider"})
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of the HTTP API by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "code"})
+	grpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_query_duration_seconds",
+		Help:      "Duration of the gRPC queries, e.g. alliance queries, by node.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"endpoint", "method"})
+	grpcFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_query_failures_total",
+		Help:      "Failed gRPC queries, e.g. alliance queries, by node.",
+	}, []string{"endpoint", "method"})
+
+	providerLastUpdateAge = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "provider_last_update_age_seconds"),
+		"Time elapsed since each provider last received a price.",
+		[]string{"provider"}, nil,
+	)
+	lastUpdates = sync.Map{} // provider -> time.Time
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		providerMessages,
+		providerErrors,
+		providerReconnects,
+		httpDuration,
+		grpcDuration,
+		grpcFailures,
+		ageCollector{},
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ProviderUpdated records a price received from the provider.
+func ProviderUpdated(provider string) {
+	providerMessages.WithLabelValues(provider).Inc()
+	lastUpdates.Store(provider, time.Now())
+}
+
+// ProviderFailed records a failed poll or a malformed message of the provider.
+func ProviderFailed(provider string) {
+	providerErrors.WithLabelValues(provider).Inc()
+}
+
+// ProviderReconnected records a reconnection of a websocket provider.
+func ProviderReconnected(provider string) {
+	providerReconnects.WithLabelValues(provider).Inc()
+}
+
+// ageCollector computes the age of the last update of each provider at scrape time.
+type ageCollector struct{}
+
+func (ageCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- providerLastUpdateAge
+}
+
+func (ageCollector) Collect(ch chan<- prometheus.Metric) {
+	lastUpdates.Range(func(provider, lastUpdate any) bool {
+		age := time.Since(lastUpdate.(time.Time)).Seconds()
+		ch <- prometheus.MustNewConstMetric(providerLastUpdateAge, prometheus.GaugeValue, age, provider.(string))
+		return true
+	})
+}
+
+// DenomStats is the aggregated price of a denom and the number of provider prices it comes from.
+type DenomStats struct {
+	Price   float64
+	Sources int
+}
+
+var (
+	denomPrice = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "denom_price"),
+		"Aggregated USD price of each denom.",
+		[]string{"denom"}, nil,
+	)
+	denomSources = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "denom_sources"),
+		"Number of provider prices each denom is aggregated from.",
+		[]string{"denom"}, nil,
+	)
+)
+
+// denomCollector computes the aggregated prices at scrape time.
+type denomCollector struct {
+	stats func() map[string]DenomStats
+}
+
+// NewDenomCollector exposes the price and the number of sources of each denom.
+func NewDenomCollector(stats func() map[string]DenomStats) prometheus.Collector {
+	return denomCollector{stats: stats}
+}
+
+func (c denomCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- denomPrice
+	ch <- denomSources
+}
+
+func (c denomCollector) Collect(ch chan<- prometheus.Metric) {
+	for denom, stats := range c.stats() {
+		ch <- prometheus.MustNewConstMetric(denomPrice, prometheus.GaugeValue, stats.Price, denom)
+		ch <- prometheus.MustNewConstMetric(denomSources, prometheus.GaugeValue, float64(stats.Sources), denom)
+	}
+}
+
+// GinMiddleware records the latency of each route.
+func GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		httpDuration.WithLabelValues(route, c.Request.Method, strconv.Itoa(c.Writer.Status())).Observe(time.Since(start).Seconds())
+	}
+}
+
+// UnaryClientInterceptor records the duration and the failures of the queries sent to a gRPC node.
+func UnaryClientInterceptor(endpoint string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		grpcDuration.WithLabelValues(endpoint, method).Observe(time.Since(start).Seconds())
+		if err != nil {
+			grpcFailures.WithLabelValues(endpoint, method).Inc()
+		}
+		return err
+	}
+}
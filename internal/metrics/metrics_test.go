@@ -0,0 +1,67 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/internal/metrics"
+	"google.golang.org/grpc"
+)
+
+func scrape(t *testing.T) string {
+	server := httptest.NewServer(metrics.Handler())
+	defer server.Close()
+	res, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	// GIVEN a provider, a route, a failing gRPC query and the aggregated prices
+	metrics.ProviderUpdated("test")
+	metrics.ProviderUpdated("test")
+	metrics.ProviderFailed("test")
+	metrics.ProviderReconnected("test")
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(metrics.GinMiddleware())
+	r.GET("/latest", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/latest", nil))
+
+	interceptor := metrics.UnaryClientInterceptor("node:9090")
+	err := interceptor(context.Background(), "/alliance.Query/Alliances", nil, nil, nil,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			return errors.New("unavailable")
+		})
+	require.Error(t, err)
+
+	collector := metrics.NewDenomCollector(func() map[string]metrics.DenomStats {
+		return map[string]metrics.DenomStats{"LUNA": {Price: 0.5, Sources: 3}}
+	})
+	metrics.Registry.MustRegister(collector)
+	defer metrics.Registry.Unregister(collector)
+
+	// WHEN
+	body := scrape(t)
+
+	// THEN
+	require.Contains(t, body, `price_server_provider_messages_total{provider="test"} 2`)
+	require.Contains(t, body, `price_server_provider_errors_total{provider="test"} 1`)
+	require.Contains(t, body, `price_server_provider_reconnects_total{provider="test"} 1`)
+	require.Contains(t, body, `price_server_provider_last_update_age_seconds{provider="test"}`)
+	require.Contains(t, body, `price_server_http_request_duration_seconds_count{code="200",method="GET",route="/latest"} 1`)
+	require.Contains(t, body, `price_server_grpc_query_duration_seconds_count{endpoint="node:9090",method="/alliance.Query/Alliances"} 1`)
+	require.Contains(t, body, `price_server_grpc_query_failures_total{endpoint="node:9090",method="/alliance.Query/Alliances"} 1`)
+	require.Contains(t, body, `price_server_denom_price{denom="LUNA"} 0.5`)
+	require.Contains(t, body, `price_server_denom_sources{denom="LUNA"} 3`)
+}
@@ -0,0 +1,282 @@
+package mockexchange_test
+
+import (
+	"context"
+	"math"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/mockexchange"
+	"github.com/terra-money/oracle-feeder-go/internal/provider"
+	"github.com/terra-money/oracle-feeder-go/pkg/types"
+)
+
+func startMockExchange(t *testing.T, script *mockexchange.Script) {
+	server := httptest.NewServer(mockexchange.NewServer(script).Handler())
+	t.Cleanup(server.Close)
+	for key, value := range mockexchange.Env(server.URL) {
+		t.Setenv(key, value)
+	}
+}
+
+func TestWebsocketProvidersReconnect(t *testing.T) {
+	// GIVEN a mock exchange that drops every connection
+	// after 3 messages, one of them being malformed
+	startMockExchange(t, &mockexchange.Script{
+		IntervalMs:      50,
+		DisconnectAfter: 3,
+		MalformedEvery:  2,
+		Prices: map[string][]float64{
+			"BTC/USDT": {30000},
+			"BTC/USD":  {30000},
+		},
+	})
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	for exchange, symbol := range map[string]string{
+		"binance": "BTCUSDT",
+		"kraken":  "XBT/USD",
+		"okx":     "BTC-USDT",
+		"kucoin":  "BTC-USDT",
+	} {
+		// WHEN
+		p, err := provider.NewProvider(exchange, &config.ProviderConfig{Symbols: []string{symbol}}, stopCh)
+		require.NoError(t, err, exchange)
+
+		// THEN prices keep flowing across several disconnections
+		lastTimestamp := func() uint64 {
+			var timestamp uint64
+			for _, price := range p.GetPrices() {
+				timestamp = price.Timestamp
+			}
+			return timestamp
+		}
+		require.Eventually(t, func() bool { return lastTimestamp() > 0 }, 5*time.Second, 10*time.Millisecond, exchange)
+		first := lastTimestamp()
+		require.Eventually(t, func() bool { return lastTimestamp() > first+500 }, 5*time.Second, 10*time.Millisecond, exchange)
+	}
+}
+
+func TestWebsocketProvidersBookTicker(t *testing.T) {
+	// GIVEN books moving around a scripted price across disconnections
+	startMockExchange(t, &mockexchange.Script{
+		IntervalMs:      50,
+		DisconnectAfter: 5,
+		MalformedEvery:  4,
+		Prices: map[string][]float64{
+			"BTC/USDT": {30000, 30100, 29900},
+			"BTC/USD":  {30000, 30100, 29900},
+		},
+	})
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	for exchange, symbol := range map[string]string{
+		"binance": "BTCUSDT",
+		"kraken":  "XBT/USD",
+		"okx":     "BTC-USDT",
+	} {
+		// WHEN
+		p, err := provider.NewProvider(exchange, &config.ProviderConfig{Symbols: []string{symbol}, Mode: "book_ticker"}, stopCh)
+		require.NoError(t, err, exchange)
+
+		// THEN the mid price follows every step of the script with the spread of the books
+		seen := make(map[float64]bool)
+		require.Eventually(t, func() bool {
+			for _, price := range p.GetPrices() {
+				require.InDelta(t, 0.001, price.Spread, 1e-9, exchange)
+				for _, step := range []float64{30000, 30100, 29900} {
+					if math.Abs(price.Price-step) < 1e-6 {
+						seen[step] = true
+					}
+				}
+			}
+			return len(seen) == 3
+		}, 5*time.Second, 10*time.Millisecond, exchange)
+	}
+
+	// a mode the exchange does not support is refused
+	_, err := provider.NewProvider("kucoin", &config.ProviderConfig{Symbols: []string{"BTC-USDT"}, Mode: "book_ticker"}, stopCh)
+	require.Error(t, err)
+}
+
+func TestWebsocketProvidersTrades(t *testing.T) {
+	// GIVEN trades alternating between two prices every 50ms
+	startMockExchange(t, &mockexchange.Script{
+		IntervalMs:      50,
+		DisconnectAfter: 10,
+		Prices: map[string][]float64{
+			"BTC/USDT": {30000, 30300},
+			"BTC/USD":  {30000, 30300},
+		},
+	})
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	for exchange, symbol := range map[string]string{
+		"binance": "BTCUSDT",
+		"kraken":  "XBT/USD",
+		"okx":     "BTC-USDT",
+	} {
+		// WHEN
+		p, err := provider.NewProvider(exchange, &config.ProviderConfig{Symbols: []string{symbol}, Mode: "trades", Window: 1}, stopCh)
+		require.NoError(t, err, exchange)
+
+		// THEN the price is the average over the window, which no single trade has
+		require.Eventually(t, func() bool {
+			for _, price := range p.GetPrices() {
+				return math.Abs(price.Price-30150) < 30
+			}
+			return false
+		}, 5*time.Second, 10*time.Millisecond, exchange)
+	}
+}
+
+func TestProviderManagerReference(t *testing.T) {
+	// GIVEN exchanges pricing BTC at 30000 while the reference oracle sees 36000
+	startMockExchange(t, &mockexchange.Script{
+		IntervalMs: 50,
+		Prices: map[string][]float64{
+			"BTC/USDT": {30000},
+			"BTC/USD":  {36000},
+			"ETH/USDT": {2000},
+			"ETH/USD":  {2010},
+			"USDT/USD": {1},
+		},
+	})
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	for _, halt := range []bool{false, true} {
+		cfg := &config.Config{
+			ProviderPriority: []string{"binance", "bitstamp"},
+			Providers: map[string]config.ProviderConfig{
+				"binance":  {Symbols: []string{"BTCUSDT", "ETHUSDT"}},
+				"bitstamp": {Symbols: []string{"usdtusd"}, Interval: 1, Timeout: 1},
+				"pyth": {Symbols: []string{
+					"e62df6c8b4a85fe1a67db44dc12de5db330f7ac66b72dc658afcf5ed4f0a4f3b", // BTC/USD
+					"ff61491a931112ddf1bd8147cd1b641375f79f5825126d665480874634fd0ace", // ETH/USD
+				}, Interval: 1, Timeout: 1},
+			},
+			Reference: config.ReferenceConfig{Provider: "pyth", MaxDeviation: 0.05, Halt: halt},
+		}
+
+		// WHEN
+		manager := provider.NewProviderManager(cfg, stopCh)
+
+		// THEN the reference is left out of the averages and BTC diverges from it
+		prices := make(map[string]types.PriceOfCoin)
+		require.Eventually(t, func() bool {
+			for _, price := range manager.GetPrices(context.Background()).Prices {
+				prices[price.Denom] = price
+			}
+			return prices["ETH"].Deviation > 0 && (halt || prices["BTC"].Deviation > 0)
+		}, 10*time.Second, 100*time.Millisecond)
+		require.InDelta(t, 2000, prices["ETH"].Price, 1e-9)
+		require.InDelta(t, 10.0/2010, prices["ETH"].Deviation, 1e-9)
+		require.False(t, prices["ETH"].Divergent)
+		if halt {
+			require.NotContains(t, prices, "BTC")
+			require.Nil(t, manager.GetPrice(context.Background(), "BTC"))
+		} else {
+			require.InDelta(t, 30000, prices["BTC"].Price, 1e-9)
+			require.InDelta(t, 1.0/6, prices["BTC"].Deviation, 1e-9)
+			require.True(t, prices["BTC"].Divergent)
+		}
+		require.Contains(t, manager.GetStatus(context.Background()).Providers, "pyth")
+	}
+}
+
+func TestProviderManagerStreamPrices(t *testing.T) {
+	// GIVEN
+	startMockExchange(t, &mockexchange.Script{
+		IntervalMs: 50,
+		Prices: map[string][]float64{
+			"BTC/USDT": {30000},
+			"ETH/USDT": {2000},
+			"USDT/USD": {1},
+		},
+	})
+	cfg := &config.Config{
+		ProviderPriority: []string{"binance", "bitstamp"},
+		Providers: map[string]config.ProviderConfig{
+			"binance":  {Symbols: []string{"BTCUSDT", "ETHUSDT"}},
+			"bitstamp": {Symbols: []string{"usdtusd"}, Interval: 1, Timeout: 1},
+		},
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	manager := provider.NewProviderManager(cfg, stopCh)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// WHEN
+	everyPrice := manager.StreamPrices(ctx, []string{"btc"}, 0)
+	bigChanges := manager.StreamPrices(ctx, []string{"btc"}, 0.01)
+
+	// THEN only BTC is sent, on every update without threshold
+	for i := 0; i < 3; i++ {
+		price, ok := <-everyPrice
+		require.True(t, ok)
+		require.Equal(t, "BTC", price.Denom)
+		require.InDelta(t, 30000, price.Price, 1e-9)
+	}
+	// and once while it does not move by 1%
+	price := <-bigChanges
+	require.Equal(t, "BTC", price.Denom)
+	select {
+	case price := <-bigChanges:
+		require.Fail(t, "unexpected update", "%+v", price)
+	case <-time.After(2 * time.Second):
+	}
+
+	// the channels are closed with the context
+	cancel()
+	require.Eventually(t, func() bool {
+		_, ok := <-everyPrice
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestProviderManagerReadiness(t *testing.T) {
+	// GIVEN BTC priced by both providers and ETH by one
+	startMockExchange(t, &mockexchange.Script{
+		IntervalMs: 50,
+		Prices: map[string][]float64{
+			"BTC/USDT": {30000},
+			"BTC/USD":  {30000},
+			"ETH/USDT": {2000},
+			"USDT/USD": {1},
+		},
+	})
+	cfg := &config.Config{
+		ProviderPriority: []string{"binance", "bitstamp"},
+		Providers: map[string]config.ProviderConfig{
+			"binance":  {Symbols: []string{"BTCUSDT", "ETHUSDT"}},
+			"bitstamp": {Symbols: []string{"btcusd", "usdtusd"}, Interval: 1, Timeout: 1},
+		},
+		Readiness: config.ReadinessConfig{MinProviders: 2, Denoms: []string{"btc", "ETH", "ATOM"}, Quorum: 2, MaxAge: 60},
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	manager := provider.NewProviderManager(cfg, stopCh)
+
+	// WHEN
+	var checks []types.ReadyCheck
+	require.Eventually(t, func() bool {
+		checks = manager.GetReadiness(context.Background())
+		return checks[0].Ok && checks[1].Ok
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// THEN ETH lacks a second source and ATOM is not priced at all
+	require.Equal(t, []types.ReadyCheck{
+		{Name: "providers", Ok: true, Detail: "2 of 2 providers serve fresh prices, 2 required"},
+		{Name: "denom:BTC", Ok: true, Detail: "2 fresh prices"},
+		{Name: "denom:ETH", Ok: false, Detail: "1 fresh prices, 2 required"},
+		{Name: "denom:ATOM", Ok: false, Detail: "no price"},
+	}, checks)
+}
@@ -0,0 +1,119 @@
+package mockexchange
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/terra-money/oracle-feeder-go/internal/parser"
+)
+
+// malformedResponse answers with a body that is not the expected JSON
+// when the script asks for it, and tells whether it did so.
+func (s *Server) malformedResponse(c *gin.Context, route string) bool {
+	if !s.script.malformed(s.countRequest(route)) {
+		return false
+	}
+	c.String(http.StatusOK, "<html>Service Temporarily Unavailable</html>")
+	return true
+}
+
+// Bitstamp OHLC data.
+//
+// API doc: https://www.bitstamp.net/api/#ohlc_data
+func (s *Server) serveBitstamp(c *gin.Context) {
+	if s.malformedResponse(c, "bitstamp") {
+		return
+	}
+	symbol := c.Param("symbol")
+	price, ok := s.priceOf("bitstamp", symbol)
+	if !ok {
+		c.JSON(http.StatusNotFound, map[string]any{"errors": []string{fmt.Sprintf("unknown pair %s", symbol)}})
+		return
+	}
+	c.JSON(http.StatusOK, map[string]any{
+		"data": map[string]any{
+			"pair": strings.ToUpper(symbol),
+			"ohlc": []map[string]string{{
+				"open":      formatFloat(price),
+				"high":      formatFloat(price),
+				"low":       formatFloat(price),
+				"close":     formatFloat(price),
+				"volume":    "1",
+				"timestamp": fmt.Sprint(time.Now().Unix()),
+			}},
+		},
+	})
+}
+
+// CoinGecko simple price, only the usd currency is supported.
+//
+// API doc: https://www.coingecko.com/api/documentation
+func (s *Server) serveCoingecko(c *gin.Context) {
+	if s.malformedResponse(c, "coingecko") {
+		return
+	}
+	res := make(map[string]map[string]float64)
+	for _, id := range strings.Split(c.Query("ids"), ",") {
+		base, quote, err := parser.ParseSymbol("coingecko", id)
+		if err != nil {
+			continue
+		}
+		if price, ok := s.script.price(base, quote, s.step()); ok {
+			res[id] = map[string]float64{"usd": price}
+		}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// Pyth latest price updates from a Hermes node, with an exponent of -8.
+//
+// API doc: https://hermes.pyth.network/docs/#/rest/latest_price_updates
+func (s *Server) servePyth(c *gin.Context) {
+	if s.malformedResponse(c, "pyth") {
+		return
+	}
+	var parsed []map[string]any
+	for _, id := range c.QueryArray("ids[]") {
+		base, quote, err := parser.ParseSymbol("pyth", id)
+		if err != nil {
+			continue
+		}
+		if price, ok := s.script.price(base, quote, s.step()); ok {
+			parsed = append(parsed, map[string]any{
+				"id": id,
+				"price": map[string]any{
+					"price":        fmt.Sprintf("%.0f", price*1e8),
+					"conf":         "0",
+					"expo":         -8,
+					"publish_time": time.Now().Unix(),
+				},
+			})
+		}
+	}
+	c.JSON(http.StatusOK, map[string]any{"parsed": parsed})
+}
+
+// Fiat providers (exchangerate.host, fer.ee, frankfurter.app) all answer
+// {"base":"USD","rates":{"EUR":0.92}}, only differing in the name of the
+// query parameter listing the currencies.
+func (s *Server) serveFiat(currenciesParam string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.malformedResponse(c, c.FullPath()) {
+			return
+		}
+		rates := make(map[string]float64)
+		for _, currency := range strings.Split(c.Query(currenciesParam), ",") {
+			if price, ok := s.script.price(currency, "USD", s.step()); ok && price > 0 {
+				rates[currency] = 1.0 / price
+			}
+		}
+		c.JSON(http.StatusOK, map[string]any{
+			"base":  "USD",
+			"date":  time.Now().UTC().Format("2006-01-02"),
+			"rates": rates,
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package mockexchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Script describes what the mock exchange serves and how it misbehaves.
+//
+// For example:
+//
+//	{
+//	    "interval_ms": 500,
+//	    "disconnect_after": 20,
+//	    "malformed_every": 7,
+//	    "prices": {
+//	        "BTC/USDT": [29000, 29100, 29050],
+//	        "USDT/USD": [1]
+//	    }
+//	}
+type Script struct {
+	// Interval between two steps of the price paths and
+	// between two messages pushed on every websocket stream.
+	IntervalMs int `json:"interval_ms,omitempty"`
+	// Close every websocket connection after it has pushed this many
+	// messages, 0 keeps connections open forever.
+	DisconnectAfter int `json:"disconnect_after,omitempty"`
+	// Replace every nth message (websocket) or response (REST) by
+	// a malformed one, 0 never sends malformed data.
+	MalformedEvery int `json:"malformed_every,omitempty"`
+	// Price path of each unified pair (e.g. BTC/USDT). The price of
+	// a pair moves to the next value of its path every interval and
+	// starts again from the beginning once the path is exhausted.
+	Prices map[string][]float64 `json:"prices"`
+}
+
+// LoadScript reads a Script from a JSON file.
+func LoadScript(path string) (*Script, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var script Script
+	if err := json.Unmarshal(bytes, &script); err != nil {
+		return nil, fmt.Errorf("failed to parse script %s: %w", path, err)
+	}
+	return &script, nil
+}
+
+func (s *Script) interval() time.Duration {
+	if s.IntervalMs <= 0 {
+		return time.Second
+	}
+	return time.Duration(s.IntervalMs) * time.Millisecond
+}
+
+// price returns the price of base/quote at the given step of its path.
+func (s *Script) price(base, quote string, step int) (float64, bool) {
+	path, ok := s.Prices[fmt.Sprintf("%s/%s", strings.ToUpper(base), strings.ToUpper(quote))]
+	if !ok || len(path) == 0 {
+		return 0, false
+	}
+	return path[step%len(path)], true
+}
+
+// malformed tells whether the nth message (starting at 1) must be malformed.
+func (s *Script) malformed(n int) bool {
+	return s.MalformedEvery > 0 && n%s.MalformedEvery == 0
+}
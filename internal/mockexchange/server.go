@@ -0,0 +1,234 @@
+package mockexchange
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/terra-money/oracle-feeder-go/internal/parser"
+)
+
+// Server speaks the wire protocols of the exchanges the price server
+// connects to, serving the prices of a Script instead of real markets.
+type Server struct {
+	script    *Script
+	startedAt time.Time
+	upgrader  websocket.Upgrader
+	mu        sync.Mutex
+	requests  map[string]int // route -> number of requests served
+}
+
+func NewServer(script *Script) *Server {
+	return &Server{
+		script:    script,
+		startedAt: time.Now(),
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		requests: make(map[string]int),
+	}
+}
+
+// Handler returns the routes of every mocked exchange.
+func (s *Server) Handler() http.Handler {
+	r := gin.New()
+	r.Use(gin.Recovery())
+
+	// websocket exchanges
+	r.GET("/binance/stream", s.serveBinance)
+	r.GET("/kraken", s.serveKraken)
+	r.GET("/okx/ws/v5/business", s.serveOkx)
+	r.GET("/okx/ws/v5/public", s.serveOkx)
+	r.POST("/kucoin/api/v1/bullet-public", s.serveKucoinToken)
+	r.GET("/kucoin/endpoint", s.serveKucoin)
+
+	// RESTful exchanges
+	r.GET("/bitstamp/api/v2/ohlc/:symbol/", s.serveBitstamp)
+	r.GET("/coingecko/api/v3/simple/price", s.serveCoingecko)
+	r.GET("/pyth/v2/updates/price/latest", s.servePyth)
+	r.GET("/exchangerate/latest", s.serveFiat("symbols"))
+	r.GET("/fer/latest", s.serveFiat("to"))
+	r.GET("/frankfurter/latest", s.serveFiat("to"))
+	return r
+}
+
+// Env returns the environment variables that point the exchange
+// adapters to a Server listening on baseUrl (e.g. http://localhost:8540).
+func Env(baseUrl string) map[string]string {
+	baseUrl = strings.TrimSuffix(baseUrl, "/")
+	wsUrl := "ws" + strings.TrimPrefix(baseUrl, "http")
+	return map[string]string{
+		"BINANCE_WEBSOCKET_URL":    wsUrl + "/binance/stream",
+		"KRAKEN_WEBSOCKET_URL":     wsUrl + "/kraken",
+		"OKX_WEBSOCKET_URL":        wsUrl + "/okx/ws/v5/business",
+		"OKX_PUBLIC_WEBSOCKET_URL": wsUrl + "/okx/ws/v5/public",
+		"KUCOIN_TOKEN_URL":         baseUrl + "/kucoin/api/v1/bullet-public",
+		"BITSTAMP_API_URL":         baseUrl + "/bitstamp/api/v2/ohlc",
+		"COINGECKO_API_URL":        baseUrl + "/coingecko/api/v3/simple/price",
+		"PYTH_API_URL":             baseUrl + "/pyth/v2/updates/price/latest",
+		"EXCHANGERATE_API_URL":     baseUrl + "/exchangerate/latest",
+		"FER_API_URL":              baseUrl + "/fer/latest",
+		"FRANKFURTER_API_URL":      baseUrl + "/frankfurter/latest",
+	}
+}
+
+// step returns the current position in the price paths.
+func (s *Server) step() int {
+	return int(time.Since(s.startedAt) / s.script.interval())
+}
+
+// priceOf returns the current price of an exchange-specific symbol.
+func (s *Server) priceOf(exchange, symbol string) (float64, bool) {
+	base, quote, err := parser.ParseSymbol(exchange, symbol)
+	if err != nil {
+		return 0, false
+	}
+	return s.script.price(base, quote, s.step())
+}
+
+// countRequest returns the number of requests served on route, including this one.
+func (s *Server) countRequest(route string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[route]++
+	return s.requests[route]
+}
+
+// session is a websocket connection with the channels subscribed on it.
+type session struct {
+	conn          *websocket.Conn
+	mu            sync.Mutex
+	subscriptions []subscription
+}
+
+// subscription is a channel (e.g. klines or book ticker) of a symbol.
+type subscription struct {
+	symbol  string
+	channel string
+}
+
+func (ss *session) write(msg []byte) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.conn.WriteMessage(websocket.TextMessage, msg)
+}
+
+func (ss *session) writeJSON(v any) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.conn.WriteJSON(v)
+}
+
+func (ss *session) subscribe(channel string, symbols ...string) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	for _, symbol := range symbols {
+		ss.subscriptions = append(ss.subscriptions, subscription{symbol: symbol, channel: channel})
+	}
+}
+
+func (ss *session) subscribed() []subscription {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return append([]subscription{}, ss.subscriptions...)
+}
+
+// wsHandler describes how a websocket exchange talks to its clients.
+type wsHandler struct {
+	exchange string
+	// greeting sent as soon as the connection is opened, if any
+	onOpen func(ss *session) error
+	// handles subscriptions and pings sent by the client
+	onMsg func(ss *session, msg []byte) error
+	// renders the candlestick or the book of a subscription at the given price
+	render func(sub subscription, price float64, now time.Time) any
+}
+
+// serveStream upgrades the connection and pushes a message for each
+// subscription every interval, following the misbehaviours of the script.
+func (s *Server) serveStream(c *gin.Context, h wsHandler) {
+	conn, err := s.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("%s upgrade failed: %v", h.exchange, err)
+		return
+	}
+	defer conn.Close()
+
+	ss := &session{conn: conn}
+	if h.onOpen != nil {
+		if err := h.onOpen(ss); err != nil {
+			log.Printf("%s: %v", h.exchange, err)
+			return
+		}
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := h.onMsg(ss, msg); err != nil {
+				log.Printf("%s: %v", h.exchange, err)
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(s.script.interval())
+	defer ticker.Stop()
+	sent := 0
+	for {
+		select {
+		case <-closed:
+			return
+		case now := <-ticker.C:
+			for _, sub := range ss.subscribed() {
+				price, ok := s.priceOf(h.exchange, sub.symbol)
+				if !ok {
+					continue
+				}
+				sent++
+				if s.script.malformed(sent) {
+					err = ss.write(malformedMsg(sent))
+				} else {
+					err = ss.writeJSON(h.render(sub, price, now))
+				}
+				if err != nil {
+					return
+				}
+				if s.script.DisconnectAfter > 0 && sent >= s.script.DisconnectAfter {
+					// drop the connection without a close frame,
+					// as an exchange going away would do
+					return
+				}
+			}
+		}
+	}
+}
+
+// malformedMsg alternates between invalid JSON and valid JSON that
+// does not have the shape of any expected message.
+func malformedMsg(n int) []byte {
+	if n%2 == 0 {
+		return []byte(`{"data":{"k":`)
+	}
+	return []byte(`{"stream":"?","data":{"k":{}}}`)
+}
+
+// halfSpread places the best bid and ask of the books around the scripted price.
+const halfSpread = 0.0005
+
+func bidAsk(price float64) (float64, float64) {
+	return price * (1 - halfSpread), price * (1 + halfSpread)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
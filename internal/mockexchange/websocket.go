@@ -0,0 +1,371 @@
+package mockexchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Binance kline, book ticker and trade streams.
+//
+// API doc: https://binance-docs.github.io/apidocs/spot/en/#kline-candlestick-streams,
+// https://binance-docs.github.io/apidocs/spot/en/#individual-symbol-book-ticker-streams
+// and https://binance-docs.github.io/apidocs/spot/en/#trade-streams
+func (s *Server) serveBinance(c *gin.Context) {
+	s.serveStream(c, wsHandler{
+		exchange: "binance",
+		onMsg: func(ss *session, msg []byte) error {
+			var command struct {
+				Id     int      `json:"id"`
+				Method string   `json:"method"`
+				Params []string `json:"params"`
+			}
+			if err := json.Unmarshal(msg, &command); err != nil {
+				return err
+			}
+			if command.Method != "SUBSCRIBE" {
+				return fmt.Errorf("unknown method %s", string(msg))
+			}
+			for _, param := range command.Params {
+				stream := strings.Split(param, "@")
+				if len(stream) != 2 {
+					return fmt.Errorf("invalid stream %s", param)
+				}
+				ss.subscribe(stream[1], strings.ToUpper(stream[0]))
+			}
+			return ss.writeJSON(map[string]any{"result": nil, "id": command.Id})
+		},
+		render: func(sub subscription, price float64, now time.Time) any {
+			symbol := sub.symbol
+			if sub.channel == "trade" {
+				return map[string]any{
+					"stream": fmt.Sprintf("%s@trade", strings.ToLower(symbol)),
+					"data": map[string]any{
+						"e": "trade",
+						"E": now.UnixMilli(),
+						"s": symbol,
+						"t": now.UnixNano(),
+						"p": formatFloat(price),
+						"q": "1",
+						"T": now.UnixMilli(),
+						"m": true,
+					},
+				}
+			}
+			if sub.channel == "bookTicker" {
+				bid, ask := bidAsk(price)
+				return map[string]any{
+					"stream": fmt.Sprintf("%s@bookTicker", strings.ToLower(symbol)),
+					"data": map[string]any{
+						"u": now.UnixNano(),
+						"s": symbol,
+						"b": formatFloat(bid),
+						"B": "1",
+						"a": formatFloat(ask),
+						"A": "1",
+					},
+				}
+			}
+			closeTime := now.UnixMilli()
+			return map[string]any{
+				"stream": fmt.Sprintf("%s@kline_1m", strings.ToLower(symbol)),
+				"data": map[string]any{
+					"e": "kline",
+					"E": closeTime,
+					"s": symbol,
+					"k": map[string]any{
+						"t": closeTime - 60_000,
+						"T": closeTime,
+						"s": symbol,
+						"i": "1m",
+						"o": formatFloat(price),
+						"h": formatFloat(price),
+						"l": formatFloat(price),
+						"c": formatFloat(price),
+						"v": "1",
+						"q": formatFloat(price),
+					},
+				},
+			}
+		},
+	})
+}
+
+// Kraken OHLC, book and trade channels.
+//
+// API doc: https://docs.kraken.com/websockets/#message-ohlc,
+// https://docs.kraken.com/websockets/#message-book
+// and https://docs.kraken.com/websockets/#message-trade
+func (s *Server) serveKraken(c *gin.Context) {
+	// best bid and ask last sent on each book of the connection
+	books := make(map[string][2]float64)
+	s.serveStream(c, wsHandler{
+		exchange: "kraken",
+		onOpen: func(ss *session) error {
+			return ss.writeJSON(map[string]any{"event": "systemStatus", "status": "online", "version": "1.9.0"})
+		},
+		onMsg: func(ss *session, msg []byte) error {
+			var command struct {
+				Event        string   `json:"event"`
+				ReqId        int      `json:"reqid"`
+				Pair         []string `json:"pair"`
+				Subscription struct {
+					Name     string `json:"name"`
+					Interval int    `json:"interval"`
+					Depth    int    `json:"depth"`
+				} `json:"subscription"`
+			}
+			if err := json.Unmarshal(msg, &command); err != nil {
+				return err
+			}
+			switch command.Event {
+			case "ping":
+				return ss.writeJSON(map[string]any{"event": "pong", "reqid": command.ReqId})
+			case "subscribe":
+				channelName := fmt.Sprintf("%s-%d", command.Subscription.Name, command.Subscription.Interval)
+				switch command.Subscription.Name {
+				case "book":
+					channelName = fmt.Sprintf("book-%d", command.Subscription.Depth)
+				case "trade":
+					channelName = "trade"
+				}
+				for _, pair := range command.Pair {
+					ss.subscribe(command.Subscription.Name, pair)
+					err := ss.writeJSON(map[string]any{
+						"event":        "subscriptionStatus",
+						"status":       "subscribed",
+						"pair":         pair,
+						"channelName":  channelName,
+						"subscription": command.Subscription,
+					})
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			default:
+				return fmt.Errorf("unknown event %s", string(msg))
+			}
+		},
+		render: func(sub subscription, price float64, now time.Time) any {
+			symbol := sub.symbol
+			seconds := float64(now.UnixMicro()) / 1e6
+			if sub.channel == "trade" {
+				return []any{
+					42,
+					[][]string{{formatFloat(price), "1", formatFloat(seconds), "b", "m", ""}},
+					"trade",
+					symbol,
+				}
+			}
+			if sub.channel == "book" {
+				bid, ask := bidAsk(price)
+				last, ok := books[symbol]
+				books[symbol] = [2]float64{bid, ask}
+				level := func(price float64, volume string) []string {
+					return []string{formatFloat(price), volume, formatFloat(seconds)}
+				}
+				if !ok {
+					return []any{
+						42,
+						map[string]any{"as": [][]string{level(ask, "1")}, "bs": [][]string{level(bid, "1")}},
+						"book-10",
+						symbol,
+					}
+				}
+				// the levels of the previous step are removed with a zero volume
+				asks, bids := [][]string{}, [][]string{}
+				if last[1] != ask {
+					asks = append(asks, level(last[1], "0.00000000"))
+				}
+				if last[0] != bid {
+					bids = append(bids, level(last[0], "0.00000000"))
+				}
+				return []any{
+					42,
+					map[string]any{"a": append(asks, level(ask, "1"))},
+					map[string]any{"b": append(bids, level(bid, "1")), "c": "0"},
+					"book-10",
+					symbol,
+				}
+			}
+			return []any{
+				42,
+				[]any{
+					formatFloat(seconds),
+					formatFloat(seconds + 60),
+					formatFloat(price),
+					formatFloat(price),
+					formatFloat(price),
+					formatFloat(price),
+					formatFloat(price),
+					"1",
+					1,
+				},
+				"ohlc-1",
+				symbol,
+			}
+		},
+	})
+}
+
+// OKX candlesticks, tickers and trades channels.
+//
+// API doc: https://www.okx.com/docs-v5/en/#websocket-api-public-channel-candlesticks-channel,
+// https://www.okx.com/docs-v5/en/#websocket-api-public-channel-tickers-channel
+// and https://www.okx.com/docs-v5/en/#websocket-api-public-channel-trades-channel
+func (s *Server) serveOkx(c *gin.Context) {
+	s.serveStream(c, wsHandler{
+		exchange: "okx",
+		onMsg: func(ss *session, msg []byte) error {
+			if string(msg) == "ping" {
+				return ss.write([]byte("pong"))
+			}
+			var command struct {
+				Op   string              `json:"op"`
+				Args []map[string]string `json:"args"`
+			}
+			if err := json.Unmarshal(msg, &command); err != nil {
+				return err
+			}
+			if command.Op != "subscribe" {
+				return fmt.Errorf("unknown op %s", string(msg))
+			}
+			for _, arg := range command.Args {
+				ss.subscribe(arg["channel"], arg["instId"])
+				if err := ss.writeJSON(map[string]any{"event": "subscribe", "arg": arg}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		render: func(sub subscription, price float64, now time.Time) any {
+			symbol := sub.symbol
+			if sub.channel == "trades" {
+				return map[string]any{
+					"arg": map[string]string{"channel": "trades", "instId": symbol},
+					"data": []map[string]string{{
+						"instId":  symbol,
+						"tradeId": fmt.Sprint(now.UnixNano()),
+						"px":      formatFloat(price),
+						"sz":      "1",
+						"side":    "buy",
+						"ts":      fmt.Sprint(now.UnixMilli()),
+					}},
+				}
+			}
+			if sub.channel == "tickers" {
+				bid, ask := bidAsk(price)
+				return map[string]any{
+					"arg": map[string]string{"channel": "tickers", "instId": symbol},
+					"data": []map[string]string{{
+						"instType": "SPOT",
+						"instId":   symbol,
+						"last":     formatFloat(price),
+						"bidPx":    formatFloat(bid),
+						"bidSz":    "1",
+						"askPx":    formatFloat(ask),
+						"askSz":    "1",
+						"ts":       fmt.Sprint(now.UnixMilli()),
+					}},
+				}
+			}
+			return map[string]any{
+				"arg": map[string]string{"channel": "candle1m", "instId": symbol},
+				"data": [][]string{{
+					fmt.Sprint(now.UnixMilli()),
+					formatFloat(price),
+					formatFloat(price),
+					formatFloat(price),
+					formatFloat(price),
+					"1",
+					formatFloat(price),
+					formatFloat(price),
+					"0",
+				}},
+			}
+		},
+	})
+}
+
+// KuCoin public token, the websocket endpoint is this same server.
+//
+// API doc: https://docs.kucoin.com/#apply-connect-token
+func (s *Server) serveKucoinToken(c *gin.Context) {
+	endpoint := fmt.Sprintf("ws://%s/kucoin/endpoint", c.Request.Host)
+	c.JSON(http.StatusOK, map[string]any{
+		"code": "200000",
+		"data": map[string]any{
+			"token": "mock-exchange",
+			"instanceServers": []map[string]any{{
+				"endpoint":     endpoint,
+				"protocol":     "websocket",
+				"pingInterval": 18000,
+				"pingTimeout":  10000,
+			}},
+		},
+	})
+}
+
+// KuCoin klines topic.
+//
+// API doc: https://docs.kucoin.com/#klines
+func (s *Server) serveKucoin(c *gin.Context) {
+	s.serveStream(c, wsHandler{
+		exchange: "kucoin",
+		onOpen: func(ss *session) error {
+			return ss.writeJSON(map[string]any{"id": "mock-exchange", "type": "welcome"})
+		},
+		onMsg: func(ss *session, msg []byte) error {
+			var command struct {
+				Id       string `json:"id"`
+				Type     string `json:"type"`
+				Topic    string `json:"topic"`
+				Response bool   `json:"response"`
+			}
+			if err := json.Unmarshal(msg, &command); err != nil {
+				return err
+			}
+			switch command.Type {
+			case "ping":
+				return ss.writeJSON(map[string]any{"id": command.Id, "type": "pong"})
+			case "subscribe":
+				topics := strings.TrimPrefix(command.Topic, "/market/candles:")
+				for _, topic := range strings.Split(topics, ",") {
+					ss.subscribe("candles", strings.TrimSuffix(topic, "_1min"))
+				}
+				if command.Response {
+					return ss.writeJSON(map[string]any{"id": command.Id, "type": "ack"})
+				}
+				return nil
+			default:
+				return fmt.Errorf("unknown type %s", string(msg))
+			}
+		},
+		render: func(sub subscription, price float64, now time.Time) any {
+			symbol := sub.symbol
+			return map[string]any{
+				"type":    "message",
+				"topic":   fmt.Sprintf("/market/candles:%s_1min", symbol),
+				"subject": "trade.candles.update",
+				"data": map[string]any{
+					"symbol": symbol,
+					"candles": []string{
+						fmt.Sprint(now.Unix()),
+						formatFloat(price),
+						formatFloat(price),
+						formatFloat(price),
+						formatFloat(price),
+						"1",
+						formatFloat(price),
+					},
+					"time": now.UnixMicro(),
+				},
+			}
+		},
+	})
+}
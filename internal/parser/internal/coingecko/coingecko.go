@@ -30,6 +30,7 @@ var COIN_GECKO_MAPPING = map[string]string{
 	"lion-dao":                   "ROAR",   // Lion DAO's token
 	"white-whale":                "WHALE",  // White Whale chain
 	"switcheo":                   "SWTH",   // Carbon chain
+	"kujira":                     "KUJI",   // Kujira chain
 	"stride-staked-luna":         "STLUNA", // Stride chain
 	"stafi-staked-swth":          "rSWTH",  // stafi-staked-swth
 	"osmosis":                    "OSMO",
@@ -0,0 +1,23 @@
+package cryptocom_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/terra-money/oracle-feeder-go/internal/parser/internal/cryptocom"
+)
+
+func TestParseSymbol(t *testing.T) {
+	base, quote, err := cryptocom.ParseSymbol("ATOM_USDT")
+	assert.NoError(t, err)
+	assert.Equal(t, "ATOM", base)
+	assert.Equal(t, "USDT", quote)
+
+	base, quote, err = cryptocom.ParseSymbol("btc_usd")
+	assert.NoError(t, err)
+	assert.Equal(t, "BTC", base)
+	assert.Equal(t, "USD", quote)
+
+	_, _, err = cryptocom.ParseSymbol("BTCUSD-PERP")
+	assert.Error(t, err)
+}
@@ -0,0 +1,15 @@
+package gateio
+
+import (
+	"fmt"
+	"strings"
+)
+
+func ParseSymbol(symbol string) (string, string, error) {
+	symbol = strings.ToUpper(symbol)
+	arr := strings.Split(symbol, "_")
+	if len(arr) != 2 {
+		return "", "", fmt.Errorf("failed to parse gateio %s", symbol)
+	}
+	return arr[0], arr[1], nil
+}
@@ -0,0 +1,23 @@
+package gateio_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/terra-money/oracle-feeder-go/internal/parser/internal/gateio"
+)
+
+func TestParseSymbol(t *testing.T) {
+	base, quote, err := gateio.ParseSymbol("INJ_USDT")
+	assert.NoError(t, err)
+	assert.Equal(t, "INJ", base)
+	assert.Equal(t, "USDT", quote)
+
+	base, quote, err = gateio.ParseSymbol("kava_usdt")
+	assert.NoError(t, err)
+	assert.Equal(t, "KAVA", base)
+	assert.Equal(t, "USDT", quote)
+
+	_, _, err = gateio.ParseSymbol("SCRTUSDT")
+	assert.Error(t, err)
+}
@@ -0,0 +1,25 @@
+package mexc_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/terra-money/oracle-feeder-go/internal/parser/internal/mexc"
+)
+
+func TestParseSymbol(t *testing.T) {
+	base, quote, err := mexc.ParseSymbol("KUJIUSDT")
+	assert.NoError(t, err)
+	assert.Equal(t, "KUJI", base)
+	assert.Equal(t, "USDT", quote)
+
+	base, quote, err = mexc.ParseSymbol("whaleusdc")
+	assert.NoError(t, err)
+	assert.Equal(t, "WHALE", base)
+	assert.Equal(t, "USDC", quote)
+
+	base, quote, err = mexc.ParseSymbol("ATOMBTC")
+	assert.NoError(t, err)
+	assert.Equal(t, "ATOM", base)
+	assert.Equal(t, "BTC", quote)
+}
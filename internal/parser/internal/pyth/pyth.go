@@ -0,0 +1,30 @@
+package pyth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// price feed id to unified pair mapping
+//
+// see https://pyth.network/developers/price-feed-ids
+var PYTH_MAPPING = map[string]string{
+	"e62df6c8b4a85fe1a67db44dc12de5db330f7ac66b72dc658afcf5ed4f0a4f3b": "BTC/USD",
+	"ff61491a931112ddf1bd8147cd1b641375f79f5825126d665480874634fd0ace": "ETH/USD",
+	"ef0d8b6fda2ceba41da15d4095d1da392a0d2f8ed0c6c7bc0f4cfac8c280b56d": "SOL/USD",
+	"b00b60f88b03a6a625a8d1c048c3f66653edf217439983d037e7222c4e612819": "ATOM/USD",
+	"eaa020c61cc479712813461ce153894a96a6c00b21ed0cfc2798d1f9a9e9c94a": "USDC/USD",
+	"2b89b9dc8fdf9f34709a5b106b472f0f39bb6ca9ce04b0fd7f2e971688e2e53b": "USDT/USD",
+	"e6ccd3f878cf338e6732bf59f60943e8ca2c28402fc4d9c258503b2edbe74a31": "LUNA/USD",
+}
+
+// ParseSymbol parses a price feed id, with or without its 0x prefix.
+func ParseSymbol(symbol string) (string, string, error) {
+	id := strings.TrimPrefix(strings.ToLower(symbol), "0x")
+	if pair, ok := PYTH_MAPPING[id]; ok {
+		arr := strings.Split(pair, "/")
+		return arr[0], arr[1], nil
+	} else {
+		return "", "", fmt.Errorf("failed to parse Pyth %s", symbol)
+	}
+}
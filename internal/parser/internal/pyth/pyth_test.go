@@ -0,0 +1,23 @@
+package pyth_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/terra-money/oracle-feeder-go/internal/parser/internal/pyth"
+)
+
+func TestParseSymbol(t *testing.T) {
+	base, quote, err := pyth.ParseSymbol("e62df6c8b4a85fe1a67db44dc12de5db330f7ac66b72dc658afcf5ed4f0a4f3b")
+	assert.NoError(t, err)
+	assert.Equal(t, "BTC", base)
+	assert.Equal(t, "USD", quote)
+
+	base, quote, err = pyth.ParseSymbol("0xFF61491A931112DDF1BD8147CD1B641375F79F5825126D665480874634FD0ACE")
+	assert.NoError(t, err)
+	assert.Equal(t, "ETH", base)
+	assert.Equal(t, "USD", quote)
+
+	_, _, err = pyth.ParseSymbol("0x00")
+	assert.Error(t, err)
+}
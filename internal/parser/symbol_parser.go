@@ -10,9 +10,13 @@ import (
 	"github.com/terra-money/oracle-feeder-go/internal/parser/internal/bitstamp"
 	"github.com/terra-money/oracle-feeder-go/internal/parser/internal/bybit"
 	"github.com/terra-money/oracle-feeder-go/internal/parser/internal/coingecko"
+	"github.com/terra-money/oracle-feeder-go/internal/parser/internal/cryptocom"
+	"github.com/terra-money/oracle-feeder-go/internal/parser/internal/gateio"
 	"github.com/terra-money/oracle-feeder-go/internal/parser/internal/huobi"
 	"github.com/terra-money/oracle-feeder-go/internal/parser/internal/kraken"
 	"github.com/terra-money/oracle-feeder-go/internal/parser/internal/kucoin"
+	"github.com/terra-money/oracle-feeder-go/internal/parser/internal/mexc"
+	"github.com/terra-money/oracle-feeder-go/internal/parser/internal/pyth"
 )
 
 // ParseSymbol parses exchange specific symbols to unified pairs.
@@ -34,12 +38,20 @@ func ParseSymbol(exhcange string, symbol string) (string, string, error) {
 		return bybit.ParseSymbol(symbol)
 	case "coingecko":
 		return coingecko.ParseSymbol(symbol)
+	case "cryptocom":
+		return cryptocom.ParseSymbol(symbol)
+	case "gateio":
+		return gateio.ParseSymbol(symbol)
 	case "huobi":
 		return huobi.ParseSymbol(symbol)
 	case "kraken":
 		return kraken.ParseSymbol(symbol)
 	case "kucoin":
 		return kucoin.ParseSymbol(symbol)
+	case "mexc":
+		return mexc.ParseSymbol(symbol)
+	case "pyth":
+		return pyth.ParseSymbol(symbol)
 	default:
 		return parseSymbolDefault(symbol)
 	}
@@ -0,0 +1,121 @@
+package alliance_provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/terra-money/oracle-feeder-go/internal/reporting"
+	"github.com/terra-money/oracle-feeder-go/internal/types"
+	pkgtypes "github.com/terra-money/oracle-feeder-go/pkg/types"
+)
+
+// Cached is a result computed in the background. AsOf is when it was
+// computed and Stale reports that it is older than the TTL, or that the
+// last refresh failed and the last good result is served instead.
+// A result older than the max age is not served at all.
+type Cached[T any] struct {
+	Value T
+	AsOf  time.Time
+	Stale bool
+}
+
+// Cache refreshes a result on an interval and keeps the last good one.
+type Cache[T any] struct {
+	name    string
+	compute func(ctx context.Context) (*T, error)
+	ttl     time.Duration
+	maxAge  time.Duration
+
+	mu     sync.RWMutex
+	value  *T
+	asOf   time.Time
+	failed bool
+	err    error
+}
+
+func NewCache[T any](name string, compute func(ctx context.Context) (*T, error), ttl, maxAge time.Duration) *Cache[T] {
+	return &Cache[T]{name: name, compute: compute, ttl: ttl, maxAge: maxAge}
+}
+
+// Run refreshes the result right away then on every interval until
+// stopCh is closed, a refresh taking longer than interval is aborted.
+func (c *Cache[T]) Run(interval time.Duration, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.refresh(interval)
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Cache[T]) refresh(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	value, err := c.computeSafely(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		log.Printf("alliance %s refresh failed: %v", c.name, err)
+		reporting.CaptureError(err, map[string]string{"alliance": c.name})
+		c.failed, c.err = true, err
+		return
+	}
+	c.value, c.asOf, c.failed, c.err = value, time.Now(), false, nil
+}
+
+// computeSafely turns the panics of the providers, e.g. carbon, into errors
+// so that the refresh loop keeps running.
+func (c *Cache[T]) computeSafely(ctx context.Context) (value *T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return c.compute(ctx)
+}
+
+// Get returns the last good result, or the refresh error when none has been computed
+// yet, or ErrNotReady when the last good result is older than the max age.
+func (c *Cache[T]) Get() (*Cached[T], error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.value == nil {
+		if c.err != nil {
+			return nil, c.err
+		}
+		return nil, fmt.Errorf("alliance %s is %w", c.name, types.ErrNotReady)
+	}
+	if c.maxAge > 0 && time.Since(c.asOf) > c.maxAge {
+		return nil, fmt.Errorf("alliance %s is %w since %s", c.name, types.ErrNotReady, c.asOf.UTC().Format(time.RFC3339))
+	}
+	return &Cached[T]{
+		Value: *c.value,
+		AsOf:  c.asOf,
+		Stale: c.failed || time.Since(c.asOf) > c.ttl,
+	}, nil
+}
+
+// Check reports whether the last refresh succeeded.
+func (c *Cache[T]) Check() pkgtypes.ReadyCheck {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	check := pkgtypes.ReadyCheck{Name: "alliance:" + c.name}
+	switch {
+	case c.failed:
+		check.Detail = fmt.Sprintf("last refresh failed: %v", c.err)
+	case c.value == nil:
+		check.Detail = types.ErrNotReady.Error()
+	default:
+		check.Ok = true
+		check.Detail = "computed at " + c.asOf.UTC().Format(time.RFC3339)
+	}
+	return check
+}
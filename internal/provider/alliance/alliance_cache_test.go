@@ -0,0 +1,124 @@
+package alliance_provider_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	alliance_provider "github.com/terra-money/oracle-feeder-go/internal/provider/alliance"
+	"github.com/terra-money/oracle-feeder-go/internal/types"
+	pkgtypes "github.com/terra-money/oracle-feeder-go/pkg/types"
+)
+
+func TestCacheKeepsLastGoodResult(t *testing.T) {
+	// GIVEN a result computed once, then failing or panicking
+	var calls atomic.Int32
+	compute := func(ctx context.Context) (*string, error) {
+		switch calls.Add(1) {
+		case 1:
+			res := "first"
+			return &res, nil
+		case 2:
+			panic("annual inflation is zero")
+		default:
+			return nil, errors.New("node unavailable")
+		}
+	}
+	cache := alliance_provider.NewCache("test", compute, time.Hour, 0)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	// WHEN
+	go cache.Run(50*time.Millisecond, stopCh)
+
+	// THEN the first result is served fresh, then stale
+	require.Eventually(t, func() bool { return calls.Load() >= 1 }, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		res, err := cache.Get()
+		return err == nil && res.Value == "first"
+	}, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return calls.Load() >= 3 }, time.Second, 10*time.Millisecond)
+	res, err := cache.Get()
+	require.NoError(t, err)
+	require.Equal(t, "first", res.Value)
+	require.True(t, res.Stale)
+	require.WithinDuration(t, time.Now(), res.AsOf, time.Second)
+	require.False(t, cache.Check().Ok)
+}
+
+func TestCacheExpires(t *testing.T) {
+	// GIVEN a result older than the TTL
+	compute := func(ctx context.Context) (*string, error) {
+		res := "result"
+		return &res, nil
+	}
+	cache := alliance_provider.NewCache("test", compute, 10*time.Millisecond, time.Hour)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go cache.Run(time.Hour, stopCh)
+	require.Eventually(t, func() bool {
+		_, err := cache.Get()
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	// WHEN
+	time.Sleep(20 * time.Millisecond)
+	res, err := cache.Get()
+
+	// THEN
+	require.NoError(t, err)
+	require.True(t, res.Stale)
+}
+
+func TestCacheNotComputed(t *testing.T) {
+	// GIVEN a result never computed successfully
+	compute := func(ctx context.Context) (*string, error) {
+		return nil, errors.New("node unavailable")
+	}
+	cache := alliance_provider.NewCache("test", compute, time.Hour, 0)
+
+	// WHEN
+	_, before := cache.Get()
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go cache.Run(time.Hour, stopCh)
+
+	// THEN
+	require.ErrorContains(t, before, "not computed yet")
+	require.Eventually(t, func() bool {
+		_, err := cache.Get()
+		return err != nil && err.Error() == "node unavailable"
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, pkgtypes.ReadyCheck{Name: "alliance:test", Detail: "last refresh failed: node unavailable"}, cache.Check())
+}
+
+func TestCacheMaxAge(t *testing.T) {
+	// GIVEN a result older than the max age, its refreshes failing
+	var calls atomic.Int32
+	compute := func(ctx context.Context) (*string, error) {
+		if calls.Add(1) > 1 {
+			return nil, errors.New("node unavailable")
+		}
+		res := "result"
+		return &res, nil
+	}
+	cache := alliance_provider.NewCache("test", compute, 10*time.Millisecond, 50*time.Millisecond)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go cache.Run(20*time.Millisecond, stopCh)
+	require.Eventually(t, func() bool {
+		_, err := cache.Get()
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	// WHEN
+	time.Sleep(100 * time.Millisecond)
+	_, err := cache.Get()
+
+	// THEN it is not served anymore
+	require.ErrorIs(t, err, types.ErrNotReady)
+	require.ErrorContains(t, err, "alliance test is not computed yet since")
+}
@@ -0,0 +1,231 @@
+package alliance_provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/terra-money/oracle-feeder-go/internal/provider"
+	types "github.com/terra-money/oracle-feeder-go/internal/types"
+	pkgtypes "github.com/terra-money/oracle-feeder-go/pkg/types"
+)
+
+// DryRunResult is the transaction the feeder would submit, simulated but not broadcast.
+type DryRunResult struct {
+	Tx *provider.SimulatedTx
+	// Changes describe what executing the transaction would change, line by line.
+	Changes []string
+}
+
+// UnsignedTx fetches the data, builds and simulates the transaction of the feeder type without signing it.
+func (a alliancesQuerierProvider) UnsignedTx(ctx context.Context) (*provider.SimulatedTx, error) {
+	msg, err := a.executeMsg()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := a.transactionsProvider.SimulateAlliancesTransaction(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("simulation failed: %w", err)
+	}
+	return tx, nil
+}
+
+// DryRun is the unsigned transaction of the feeder type and what it would change.
+func (a alliancesQuerierProvider) DryRun(ctx context.Context) (*DryRunResult, error) {
+	tx, err := a.UnsignedTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := a.describeChanges(ctx, tx.Msg.Msg)
+	if err != nil {
+		return nil, err
+	}
+	return &DryRunResult{Tx: tx, Changes: changes}, nil
+}
+
+func (a alliancesQuerierProvider) describeChanges(ctx context.Context, msg []byte) ([]string, error) {
+	switch a.feederType {
+	case types.AllianceRebalanceFeeder:
+		var redelegate pkgtypes.MsgAllianceRedelegate
+		if err := json.Unmarshal(msg, &redelegate); err != nil {
+			return nil, err
+		}
+		return DescribeRedelegations(redelegate.AllianceRedelegate.Redelegations), nil
+	case types.AllianceInitialDelegation:
+		var delegate pkgtypes.MsgAllianceDelegations
+		if err := json.Unmarshal(msg, &delegate); err != nil {
+			return nil, err
+		}
+		changes := []string{fmt.Sprintf("%d delegations", len(delegate.AllianceDelegations.AllianceDelegations))}
+		for _, d := range delegate.AllianceDelegations.AllianceDelegations {
+			changes = append(changes, fmt.Sprintf("  %s: +%s", d.Validator, d.Amount))
+		}
+		return changes, nil
+	case types.AllianceOracleFeeder:
+		var update pkgtypes.MsgUpdateChainsInfo
+		if err := json.Unmarshal(msg, &update); err != nil {
+			return nil, err
+		}
+		// the unsafe query answers the chains info even when it is outdated
+		var current []types.ProtocolInfo
+		var changes []string
+		res, err := a.transactionsProvider.QueryContract(ctx, []byte(`{"query_chains_info_unsafe":{}}`))
+		if err == nil {
+			err = json.Unmarshal(res, &current)
+		}
+		if err != nil {
+			changes = append(changes, fmt.Sprintf("the chains info on chain is unavailable, compared to none: %v", err))
+		}
+		return append(changes, DiffChainsInfo(current, update.UpdateChainsInfo.ChainsInfo)...), nil
+	default:
+		return nil, nil
+	}
+}
+
+// DescribeRedelegations lists the redelegations then the net change of the stake of each validator.
+func DescribeRedelegations(redelegations []types.Redelegation) []string {
+	changes := []string{fmt.Sprintf("%d redelegations", len(redelegations))}
+	net := make(map[string]sdk.Int)
+	add := func(validator string, amount sdk.Int) {
+		if total, ok := net[validator]; ok {
+			amount = total.Add(amount)
+		}
+		net[validator] = amount
+	}
+	for _, r := range redelegations {
+		changes = append(changes, fmt.Sprintf("  %s -> %s: %s", r.SrcValidator, r.DstValidator, r.Amount))
+		amount, ok := sdk.NewIntFromString(r.Amount)
+		if !ok {
+			continue
+		}
+		add(r.SrcValidator, amount.Neg())
+		add(r.DstValidator, amount)
+	}
+	if len(net) == 0 {
+		return changes
+	}
+
+	validators := make([]string, 0, len(net))
+	for validator := range net {
+		validators = append(validators, validator)
+	}
+	sort.Strings(validators)
+	changes = append(changes, "net stake changes")
+	for _, validator := range validators {
+		amount := net[validator]
+		sign := ""
+		if amount.IsPositive() {
+			sign = "+"
+		}
+		changes = append(changes, fmt.Sprintf("  %s: %s%s", validator, sign, amount))
+	}
+	return changes
+}
+
+// DiffChainsInfo compares the chains info on chain with the update, chain by chain.
+func DiffChainsInfo(current []types.ProtocolInfo, update types.AllianceProtocolRes) []string {
+	changes := []string{fmt.Sprintf("luna price: %s", decString(update.LunaPrice))}
+	currentByChain := make(map[string]types.ProtocolInfo, len(current))
+	for _, info := range current {
+		currentByChain[info.ChainId] = info
+	}
+	for _, info := range update.ProtocolsInfo {
+		old, ok := currentByChain[info.ChainId]
+		delete(currentByChain, info.ChainId)
+		if !ok {
+			changes = append(changes, fmt.Sprintf("+ %s: %s at %s, annual provisions %s, %d luna alliances, %d chain alliances",
+				info.ChainId,
+				info.NativeToken.Denom,
+				decString(info.NativeToken.TokenPrice),
+				decString(info.NativeToken.AnnualProvisions),
+				len(info.LunaAlliances),
+				len(info.ChainAlliancesOnPhoenix),
+			))
+			continue
+		}
+		chainChanges := diffProtocolInfo(old, info)
+		if len(chainChanges) == 0 {
+			changes = append(changes, fmt.Sprintf("  %s: unchanged", info.ChainId))
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("~ %s:", info.ChainId))
+		for _, change := range chainChanges {
+			changes = append(changes, "    "+change)
+		}
+	}
+	removed := make([]string, 0, len(currentByChain))
+	for chainId := range currentByChain {
+		removed = append(removed, chainId)
+	}
+	sort.Strings(removed)
+	for _, chainId := range removed {
+		changes = append(changes, fmt.Sprintf("- %s", chainId))
+	}
+	return changes
+}
+
+func diffProtocolInfo(old, updated types.ProtocolInfo) []string {
+	var changes []string
+	diff := func(name string, from, to sdk.Dec) {
+		if decString(from) != decString(to) {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", name, decString(from), decString(to)))
+		}
+	}
+	if old.NativeToken.Denom != updated.NativeToken.Denom {
+		changes = append(changes, fmt.Sprintf("native token: %s -> %s", old.NativeToken.Denom, updated.NativeToken.Denom))
+	}
+	diff("token price", old.NativeToken.TokenPrice, updated.NativeToken.TokenPrice)
+	diff("annual provisions", old.NativeToken.AnnualProvisions, updated.NativeToken.AnnualProvisions)
+
+	oldLunaAlliances := make(map[string]types.LunaAlliance)
+	for _, alliance := range old.LunaAlliances {
+		oldLunaAlliances[alliance.IBCDenom] = alliance
+	}
+	for _, alliance := range updated.LunaAlliances {
+		oldAlliance, ok := oldLunaAlliances[alliance.IBCDenom]
+		delete(oldLunaAlliances, alliance.IBCDenom)
+		if !ok {
+			changes = append(changes, fmt.Sprintf("+ luna alliance %s", alliance.IBCDenom))
+			continue
+		}
+		diff(alliance.IBCDenom+" normalized reward weight", oldAlliance.NormalizedRewardWeight, alliance.NormalizedRewardWeight)
+		diff(alliance.IBCDenom+" annual take rate", oldAlliance.AnnualTakeRate, alliance.AnnualTakeRate)
+		diff(alliance.IBCDenom+" total lsd staked", oldAlliance.TotalLSDStaked, alliance.TotalLSDStaked)
+		diff(alliance.IBCDenom+" rebase factor", oldAlliance.RebaseFactor, alliance.RebaseFactor)
+	}
+	for _, alliance := range old.LunaAlliances {
+		if _, ok := oldLunaAlliances[alliance.IBCDenom]; ok {
+			changes = append(changes, fmt.Sprintf("- luna alliance %s", alliance.IBCDenom))
+		}
+	}
+
+	oldChainAlliances := make(map[string]types.BaseAlliance)
+	for _, alliance := range old.ChainAlliancesOnPhoenix {
+		oldChainAlliances[alliance.IBCDenom] = alliance
+	}
+	for _, alliance := range updated.ChainAlliancesOnPhoenix {
+		oldAlliance, ok := oldChainAlliances[alliance.IBCDenom]
+		delete(oldChainAlliances, alliance.IBCDenom)
+		if !ok {
+			changes = append(changes, fmt.Sprintf("+ chain alliance %s", alliance.IBCDenom))
+			continue
+		}
+		diff(alliance.IBCDenom+" rebase factor", oldAlliance.RebaseFactor, alliance.RebaseFactor)
+	}
+	for _, alliance := range old.ChainAlliancesOnPhoenix {
+		if _, ok := oldChainAlliances[alliance.IBCDenom]; ok {
+			changes = append(changes, fmt.Sprintf("- chain alliance %s", alliance.IBCDenom))
+		}
+	}
+	return changes
+}
+
+// decString prints the decimals the contracts and the price server leave out as 0.
+func decString(d sdk.Dec) string {
+	if d.IsNil() {
+		return "0"
+	}
+	return d.String()
+}
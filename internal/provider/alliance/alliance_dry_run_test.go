@@ -0,0 +1,82 @@
+package alliance_provider_test
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	alliance_provider "github.com/terra-money/oracle-feeder-go/internal/provider/alliance"
+	types "github.com/terra-money/oracle-feeder-go/internal/types"
+)
+
+func TestDescribeRedelegations(t *testing.T) {
+	// GIVEN
+	redelegations := []types.Redelegation{
+		types.NewRedelegation("val3", "val1", "5"),
+		types.NewRedelegation("val3", "val2", "10"),
+		types.NewRedelegation("val2", "val1", "4"),
+	}
+
+	// WHEN
+	changes := alliance_provider.DescribeRedelegations(redelegations)
+
+	// THEN
+	require.Equal(t, []string{
+		"3 redelegations",
+		"  val3 -> val1: 5",
+		"  val3 -> val2: 10",
+		"  val2 -> val1: 4",
+		"net stake changes",
+		"  val1: +9",
+		"  val2: +6",
+		"  val3: -15",
+	}, changes)
+}
+
+func TestDiffChainsInfo(t *testing.T) {
+	// GIVEN the chains info on chain
+	current := []types.ProtocolInfo{
+		types.NewProtocolInfo(
+			"carbon-1",
+			types.NewNativeToken("swth", sdk.MustNewDecFromStr("0.005"), sdk.NewDec(1000)),
+			[]types.LunaAlliance{
+				types.NewLunaAlliance("ibc/AMPLUNA", sdk.MustNewDecFromStr("0.5"), sdk.ZeroDec(), sdk.NewDec(100), sdk.OneDec()),
+				types.NewLunaAlliance("ibc/BLUNA", sdk.MustNewDecFromStr("0.5"), sdk.ZeroDec(), sdk.NewDec(100), sdk.OneDec()),
+			},
+			[]types.BaseAlliance{types.NewBaseAlliance("ibc/SWTH", sdk.OneDec())},
+		),
+		types.NewProtocolInfo("juno-1", types.NewNativeToken("ujuno", sdk.OneDec(), sdk.OneDec()), nil, nil),
+		types.NewProtocolInfo("migaloo-1", types.NewNativeToken("uwhale", sdk.OneDec(), sdk.OneDec()), nil, nil),
+	}
+	// and the update
+	update := types.AllianceProtocolRes{
+		LunaPrice: sdk.MustNewDecFromStr("0.6"),
+		ProtocolsInfo: []types.ProtocolInfo{
+			types.NewProtocolInfo(
+				"carbon-1",
+				types.NewNativeToken("swth", sdk.MustNewDecFromStr("0.006"), sdk.NewDec(1000)),
+				[]types.LunaAlliance{
+					types.NewLunaAlliance("ibc/AMPLUNA", sdk.OneDec(), sdk.ZeroDec(), sdk.NewDec(100), sdk.OneDec()),
+				},
+				[]types.BaseAlliance{types.NewBaseAlliance("ibc/SWTH", sdk.OneDec())},
+			),
+			types.NewProtocolInfo("migaloo-1", types.NewNativeToken("uwhale", sdk.OneDec(), sdk.OneDec()), nil, nil),
+			types.NewProtocolInfo("kaiyo-1", types.NewNativeToken("ukuji", sdk.NewDec(2), sdk.NewDec(3)), nil, nil),
+		},
+	}
+
+	// WHEN
+	changes := alliance_provider.DiffChainsInfo(current, update)
+
+	// THEN
+	require.Equal(t, []string{
+		"luna price: 0.600000000000000000",
+		"~ carbon-1:",
+		"    token price: 0.005000000000000000 -> 0.006000000000000000",
+		"    ibc/AMPLUNA normalized reward weight: 0.500000000000000000 -> 1.000000000000000000",
+		"    - luna alliance ibc/BLUNA",
+		"  migaloo-1: unchanged",
+		"+ kaiyo-1: ukuji at 2.000000000000000000, annual provisions 3.000000000000000000, 0 luna alliances, 0 chain alliances",
+		"- juno-1",
+	}, changes)
+}
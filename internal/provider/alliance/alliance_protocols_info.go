@@ -67,7 +67,7 @@ func (p *allianceProtocolsInfo) GetProtocolsInfo(ctx context.Context) (*pkgtypes
 	// Setup Luna price
 	for _, price := range pricesRes.Prices {
 		if strings.EqualFold(price.Denom, "LUNA") {
-			luna, err := sdktypes.NewDecFromStr(strconv.FormatFloat(price.Price, 'f', -1, 64))
+			luna, err := decPrice(price)
 			if err != nil {
 				fmt.Printf("parse price error for: %s %v \n", price.Denom, err)
 				return nil, err
@@ -81,7 +81,7 @@ func (p *allianceProtocolsInfo) GetProtocolsInfo(ctx context.Context) (*pkgtypes
 	for _, grpcUrl := range p.config.GRPCUrls {
 		grpcConn, err := p.BaseGrpc.Connection(ctx, grpcUrl)
 		if err != nil {
-			return nil, err
+			return nil, types.NewUpstreamError(grpcUrl, err)
 		}
 		defer grpcConn.Close()
 
@@ -95,7 +95,7 @@ func (p *allianceProtocolsInfo) GetProtocolsInfo(ctx context.Context) (*pkgtypes
 		allianceRes, err := allianceClient.Alliances(ctx, &alliancetypes.QueryAlliancesRequest{})
 		if err != nil {
 			fmt.Printf("allianceRes for %s: %v \n", grpcUrl, err)
-			return nil, err
+			return nil, types.NewUpstreamError(grpcUrl, err)
 		}
 		if len(allianceRes.Alliances) == 0 {
 			fmt.Printf("No alliances found on: %s \n", grpcUrl)
@@ -105,19 +105,19 @@ func (p *allianceProtocolsInfo) GetProtocolsInfo(ctx context.Context) (*pkgtypes
 		allianceParamsRes, err := allianceClient.Params(ctx, &alliancetypes.QueryParamsRequest{})
 		if err != nil {
 			fmt.Printf("allianceParamsRes: %v \n", err)
-			return nil, err
+			return nil, types.NewUpstreamError(grpcUrl, err)
 		}
 
 		nodeRes, err := nodeClient.GetNodeInfo(ctx, &tmservice.GetNodeInfoRequest{})
 		if err != nil {
 			fmt.Printf("nodeRes: %v \n", err)
-			return nil, err
+			return nil, types.NewUpstreamError(grpcUrl, err)
 		}
 
 		stakingParamsRes, err := stakingClient.Params(ctx, &stakingtypes.QueryParamsRequest{})
 		if err != nil {
 			fmt.Printf("stakingParamsRes: %v \n", err)
-			return nil, err
+			return nil, types.NewUpstreamError(grpcUrl, err)
 		}
 
 		var annualProvisionsRes *mintypes.QueryAnnualProvisionsResponse
@@ -125,11 +125,13 @@ func (p *allianceProtocolsInfo) GetProtocolsInfo(ctx context.Context) (*pkgtypes
 			annualProvisionsRes, err = p.CarbonProvider.GetAnnualProvisions(ctx)
 		} else {
 			annualProvisionsRes, err = mintClient.AnnualProvisions(ctx, &mintypes.QueryAnnualProvisionsRequest{})
-			annualProvisionsRes.AnnualProvisions = annualProvisionsRes.AnnualProvisions.QuoInt64(1000000)
 		}
 		if err != nil {
 			fmt.Printf("annualProvisionsRes: %v \n", err)
-			return nil, err
+			return nil, types.NewUpstreamError(grpcUrl, err)
+		}
+		if !strings.Contains(grpcUrl, "carbon") {
+			annualProvisionsRes.AnnualProvisions = annualProvisionsRes.AnnualProvisions.QuoInt64(1000000)
 		}
 
 		// Remove the "u" prefix from the bond denom and
@@ -148,7 +150,7 @@ func (p *allianceProtocolsInfo) GetProtocolsInfo(ctx context.Context) (*pkgtypes
 			return nil, fmt.Errorf("price not found for: %s", bondDenom)
 		}
 
-		price, err := sdktypes.NewDecFromStr(strconv.FormatFloat(priceRes.Price, 'f', -1, 64))
+		price, err := decPrice(priceRes)
 		if err != nil {
 			fmt.Printf("parse price error for: %s %v \n", bondDenom, err)
 			return nil, err
@@ -175,6 +177,15 @@ func (p *allianceProtocolsInfo) GetProtocolsInfo(ctx context.Context) (*pkgtypes
 	return &res, nil
 }
 
+// decPrice is the price of the coin the chains info is updated with,
+// the prices diverging from the reference are refused.
+func decPrice(price pkgtypes.PriceOfCoin) (sdktypes.Dec, error) {
+	if price.Divergent {
+		return sdktypes.Dec{}, fmt.Errorf("price of %s deviates by %f from the reference", price.Denom, price.Deviation)
+	}
+	return sdktypes.NewDecFromStr(strconv.FormatFloat(price.Price, 'f', -1, 64))
+}
+
 func (p *allianceProtocolsInfo) queryRebaseFactors(ctx context.Context, configLST []config.LSTData) ([]config.LSTData, error) {
 	for i, lst := range configLST {
 		rebaseFactor, err := p.LSDProvider.QueryLSTRebaseFactor(ctx, lst.Symbol)
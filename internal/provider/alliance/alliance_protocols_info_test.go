@@ -0,0 +1,24 @@
+package alliance_provider
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	pkgtypes "github.com/terra-money/oracle-feeder-go/pkg/types"
+)
+
+func TestDecPrice(t *testing.T) {
+	// GIVEN a price agreeing with the reference and a divergent one
+	price := pkgtypes.PriceOfCoin{Denom: "WHALE", Price: 0.02, Deviation: 0.01}
+	divergent := pkgtypes.PriceOfCoin{Denom: "LUNA", Price: 0.9, Deviation: 0.5, Divergent: true}
+
+	// WHEN
+	dec, err := decPrice(price)
+	_, divergentErr := decPrice(divergent)
+
+	// THEN only the divergent price is refused
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("0.02"), dec)
+	require.EqualError(t, divergentErr, "price of LUNA deviates by 0.500000 from the reference")
+}
@@ -2,34 +2,54 @@ package alliance_provider
 
 import (
 	"context"
+	"time"
 
 	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/reporting"
 	"github.com/terra-money/oracle-feeder-go/pkg/types"
 
 	"github.com/terra-money/oracle-feeder-go/internal/provider"
 )
 
+// allianceProvider serves the alliance messages computed in the background,
+// so that a slow node or API does not fail the requests.
 type allianceProvider struct {
-	allianceProtocolsInfo      *allianceProtocolsInfo
-	allianceValidatorsProvider *allianceValidatorsProvider
+	protocolsInfo      *Cache[types.MsgUpdateChainsInfo]
+	redelegations      *Cache[types.MsgAllianceRedelegate]
+	initialDelegations *Cache[types.MsgAllianceDelegations]
 }
 
-func NewAllianceProvider(config *config.AllianceConfig, providerManager *provider.ProviderManager) *allianceProvider {
-
-	return &allianceProvider{
-		allianceProtocolsInfo:      NewAllianceProtocolsInfo(config, providerManager),
-		allianceValidatorsProvider: NewAllianceValidatorsProvider(config, providerManager),
+func NewAllianceProvider(config *config.AllianceConfig, providerManager *provider.ProviderManager, stopCh <-chan struct{}) *allianceProvider {
+	allianceProtocolsInfo := NewAllianceProtocolsInfo(config, providerManager)
+	allianceValidatorsProvider := NewAllianceValidatorsProvider(config, providerManager)
+
+	interval := time.Duration(config.RefreshInterval) * time.Second
+	ttl := time.Duration(config.CacheTTL) * time.Second
+	maxAge := time.Duration(config.CacheMaxAge) * time.Second
+	p := &allianceProvider{
+		protocolsInfo:      NewCache("protocol", allianceProtocolsInfo.GetProtocolsInfo, ttl, maxAge),
+		redelegations:      NewCache("rebalance", allianceValidatorsProvider.GetAllianceRedelegateReq, ttl, maxAge),
+		initialDelegations: NewCache("delegations", allianceValidatorsProvider.GetAllianceInitialDelegations, ttl, maxAge),
 	}
+	reporting.Go(func() { p.protocolsInfo.Run(interval, stopCh) })
+	reporting.Go(func() { p.redelegations.Run(interval, stopCh) })
+	reporting.Go(func() { p.initialDelegations.Run(interval, stopCh) })
+	return p
+}
+
+func (p *allianceProvider) GetProtocolsInfo(ctx context.Context) (*Cached[types.MsgUpdateChainsInfo], error) {
+	return p.protocolsInfo.Get()
 }
 
-func (p *allianceProvider) GetProtocolsInfo(ctx context.Context) (*types.MsgUpdateChainsInfo, error) {
-	return p.allianceProtocolsInfo.GetProtocolsInfo(ctx)
+func (p *allianceProvider) GetAllianceRedelegateReq(ctx context.Context) (*Cached[types.MsgAllianceRedelegate], error) {
+	return p.redelegations.Get()
 }
 
-func (p *allianceProvider) GetAllianceRedelegateReq(ctx context.Context) (*types.MsgAllianceRedelegate, error) {
-	return p.allianceValidatorsProvider.GetAllianceRedelegateReq(ctx)
+func (p *allianceProvider) GetAllianceInitialDelegations(ctx context.Context) (*Cached[types.MsgAllianceDelegations], error) {
+	return p.initialDelegations.Get()
 }
 
-func (p *allianceProvider) GetAllianceInitialDelegations(ctx context.Context) (*types.MsgAllianceDelegations, error) {
-	return p.allianceValidatorsProvider.GetAllianceInitialDelegations(ctx)
+// GetReadiness reports whether the last refresh of each alliance message succeeded.
+func (p *allianceProvider) GetReadiness() []types.ReadyCheck {
+	return []types.ReadyCheck{p.protocolsInfo.Check(), p.redelegations.Check(), p.initialDelegations.Check()}
 }
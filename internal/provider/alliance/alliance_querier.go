@@ -5,11 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"os"
 
 	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/terra-money/oracle-feeder-go/internal/auth"
 	"github.com/terra-money/oracle-feeder-go/internal/provider"
+	"github.com/terra-money/oracle-feeder-go/internal/reporting"
+	"github.com/terra-money/oracle-feeder-go/internal/signature"
 	types "github.com/terra-money/oracle-feeder-go/internal/types"
 	pkgtypes "github.com/terra-money/oracle-feeder-go/pkg/types"
 )
@@ -18,27 +22,43 @@ type alliancesQuerierProvider struct {
 	feederType           types.FeederType
 	transactionsProvider provider.TransactionsProvider
 	telegramProvider     *provider.TelegramProvider
+	verifier             *signature.Verifier
+	credentials          *auth.Credentials
+	acceptStale          bool
 }
 
 func NewAlliancesQuerierProvider(feederType types.FeederType) *alliancesQuerierProvider {
+	verifier, err := signature.NewVerifierFromEnv()
+	if err != nil {
+		panic(err)
+	}
+	if verifier == nil {
+		log.Print("PRICE_SERVER_PUBLIC_KEY is not set, the price server responses are not verified")
+	}
 	return &alliancesQuerierProvider{
 		telegramProvider:     provider.NewTelegramProvider(),
 		feederType:           feederType,
 		transactionsProvider: provider.NewTransactionsProvider(feederType),
+		verifier:             verifier,
+		credentials:          auth.CredentialsFromEnv(),
+		acceptStale:          os.Getenv("FEEDER_ACCEPT_STALE") == "true",
 	}
 }
 
+// Signer returns the address signing the transactions of the feeder.
+func (a alliancesQuerierProvider) Signer() string {
+	return a.transactionsProvider.Address()
+}
+
 func (a alliancesQuerierProvider) SubmitTx(ctx context.Context) (hash string, err error) {
-	if a.feederType == types.AllianceOracleFeeder ||
-		a.feederType == types.AllianceRebalanceFeeder ||
-		a.feederType == types.AllianceInitialDelegation {
-		hash, err = a.QueryAndSubmitOnChain(ctx)
-	} else {
-		hash, err = a.SubmitOnChain(ctx)
+	msg, err := a.executeMsg()
+	if err == nil {
+		hash, err = a.transactionsProvider.SubmitAlliancesTransaction(ctx, msg)
 	}
 
 	if err != nil {
 		_ = a.telegramProvider.SendError(string(a.feederType), err)
+		reporting.CaptureError(err, map[string]string{"feeder_type": string(a.feederType)})
 		return "", err
 	}
 
@@ -47,25 +67,21 @@ func (a alliancesQuerierProvider) SubmitTx(ctx context.Context) (hash string, er
 	return hash, err
 }
 
-func (a alliancesQuerierProvider) QueryAndSubmitOnChain(ctx context.Context) (string, error) {
-	res, err := a.requestData()
-	if err != nil {
-		return "", fmt.Errorf("ERROR querying alliances data %w", err)
-	}
-	return a.transactionsProvider.SubmitAlliancesTransaction(ctx, res)
-}
-
-func (a alliancesQuerierProvider) SubmitOnChain(ctx context.Context) (string, error) {
-	var sdkMsg wasmtypes.RawContractMessage
-
+// executeMsg returns the message the feeder type executes, requesting
+// the price server for the types submitting its data.
+func (a alliancesQuerierProvider) executeMsg() (wasmtypes.RawContractMessage, error) {
 	switch a.feederType {
 	case types.AllianceRebalanceEmissions:
-		sdkMsg, _ = json.Marshal(pkgtypes.MsgRebalanceEmissions{})
+		return json.Marshal(pkgtypes.MsgRebalanceEmissions{})
 	case types.AllianceUpdateRewards:
-		sdkMsg, _ = json.Marshal(pkgtypes.MsgUpdateRewards{})
+		return json.Marshal(pkgtypes.MsgUpdateRewards{})
 	}
 
-	return a.transactionsProvider.SubmitAlliancesTransaction(ctx, sdkMsg)
+	res, err := a.requestData()
+	if err != nil {
+		return nil, fmt.Errorf("ERROR querying alliances data %w", err)
+	}
+	return res, nil
 }
 
 func (a alliancesQuerierProvider) requestData() (res []byte, err error) {
@@ -73,8 +89,16 @@ func (a alliancesQuerierProvider) requestData() (res []byte, err error) {
 	if url = os.Getenv("PRICE_SERVER_URL"); len(url) == 0 {
 		url = "http://localhost:8532"
 	}
+	path := types.FromFeederTypeToPriceServerUrl(a.feederType)
+	req, err := http.NewRequest(http.MethodGet, url+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if a.credentials != nil {
+		a.credentials.Authorize(req, nil)
+	}
 	// Send GET request
-	resp, err := http.Get(url + types.FromFeederTypeToPriceServerUrl(a.feederType))
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -86,10 +110,41 @@ func (a alliancesQuerierProvider) requestData() (res []byte, err error) {
 		return nil, err
 	}
 
+	// The errors are reported as is, e.g. a 401 of a proxy, as they never end up on chain
+	if resp.StatusCode != http.StatusOK {
+		return nil, priceServerError(path, resp.StatusCode, body)
+	}
+
+	// Reject what the price server did not sign before it ends up on chain
+	if a.verifier != nil {
+		if err := a.verifier.Verify(path, resp.Header, body); err != nil {
+			return nil, fmt.Errorf("price server response rejected: %w", err)
+		}
+	}
+
+	// A stale result is only submitted on chain when the operator opted in
+	if resp.Header.Get(signature.StaleHeader) == "true" {
+		asOf := resp.Header.Get(signature.AsOfHeader)
+		if !a.acceptStale {
+			return nil, fmt.Errorf("price server serves a stale result computed at %s, set FEEDER_ACCEPT_STALE=true to submit it", asOf)
+		}
+		log.Printf("price server serves a stale result computed at %s", asOf)
+	}
+
 	// Access parsed data
 	return body, nil
 }
 
+// priceServerError returns the APIError of a failed request to the price
+// server, or its status and body when it did not answer an ErrorResponse.
+func priceServerError(path string, status int, body []byte) error {
+	var res pkgtypes.ErrorResponse
+	if err := json.Unmarshal(body, &res); err != nil || res.Error.Code == "" {
+		return fmt.Errorf("price server %s answered %d: %s", path, status, body)
+	}
+	return fmt.Errorf("price server %s answered %d: %w", path, status, res.Error)
+}
+
 func (a alliancesQuerierProvider) sendSuccessTelegramMessage(hash string) error {
 	url := fmt.Sprintf("<a href='https://finder.terra.money/%s/tx/%s'>(transaction)</a>", a.transactionsProvider.ChainId, hash)
 	var msg string
@@ -392,7 +392,7 @@ func (p *allianceValidatorsProvider) querySmartContractConfig(ctx context.Contex
 	grpcConn, err := p.BaseGrpc.Connection(ctx, p.nodeGrpcUrl)
 	if err != nil {
 		fmt.Printf("grpcConn: %v \n", err)
-		return nil, err
+		return nil, types.NewUpstreamError(p.nodeGrpcUrl, err)
 	}
 	defer grpcConn.Close()
 	client := wasmtypes.NewQueryClient(grpcConn)
@@ -401,13 +401,13 @@ func (p *allianceValidatorsProvider) querySmartContractConfig(ctx context.Contex
 		QueryData: []byte(`{ "config" : {}}`),
 	})
 	if err != nil {
-		return nil, err
+		return nil, types.NewUpstreamError(p.allianceHubContractAddress, err)
 	}
 
 	var configRes types.AllianceHubConfigData
 	err = json.Unmarshal(res.Data, &configRes)
 	if err != nil {
-		return nil, err
+		return nil, types.NewUpstreamError(p.allianceHubContractAddress, err)
 	}
 
 	return &configRes, nil
@@ -423,7 +423,7 @@ func (p *allianceValidatorsProvider) queryValidatorsData(ctx context.Context) (
 	grpcConn, err := p.BaseGrpc.Connection(ctx, p.nodeGrpcUrl)
 	if err != nil {
 		fmt.Printf("grpcConn: %v \n", err)
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, types.NewUpstreamError(p.nodeGrpcUrl, err)
 	}
 	defer grpcConn.Close()
 
@@ -439,13 +439,13 @@ func (p *allianceValidatorsProvider) queryValidatorsData(ctx context.Context) (
 	})
 	if err != nil {
 		fmt.Printf("valsRes: %v \n", err)
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, types.NewUpstreamError(p.nodeGrpcUrl, err)
 	}
 
 	latestHeightRes, err := nodeClient.GetLatestBlock(ctx, &tmservice.GetLatestBlockRequest{})
 	if err != nil {
 		fmt.Printf("latestHeightRes: %v \n", err)
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, types.NewUpstreamError(p.nodeGrpcUrl, err)
 	}
 
 	seniorValidatorsRes, err := nodeClient.GetValidatorSetByHeight(ctx, &tmservice.GetValidatorSetByHeightRequest{
@@ -453,7 +453,7 @@ func (p *allianceValidatorsProvider) queryValidatorsData(ctx context.Context) (
 	})
 	if err != nil {
 		fmt.Printf("seniorValidatorsRes: %v \n", err)
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, types.NewUpstreamError(p.nodeGrpcUrl, err)
 	}
 
 	proposalsVotesRes, err := p.getProposals(ctx)
@@ -469,7 +469,7 @@ func (p *allianceValidatorsProvider) queryValidatorsData(ctx context.Context) (
 	})
 	if err != nil {
 		fmt.Printf("allianceVals: %v \n", err)
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, types.NewUpstreamError(p.nodeGrpcUrl, err)
 	}
 
 	return valsRes.Validators,
@@ -519,28 +519,28 @@ func (p allianceValidatorsProvider) queryProposalIDs(proposalStatus string) (pro
 	// Send GET request
 	res, err := http.Get(url)
 	if err != nil {
-		return nil, err
+		return nil, types.NewUpstreamError(p.terraLcdUrl, err)
 	}
 	defer res.Body.Close()
 
 	// Read response body
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return nil, err
+		return nil, types.NewUpstreamError(p.terraLcdUrl, err)
 	}
 	var resGov *types.GovRes
 
 	// Parse JSON response into struct
 	err = json.Unmarshal(body, &resGov)
 	if err != nil {
-		return nil, err
+		return nil, types.NewUpstreamError(p.terraLcdUrl, err)
 	}
 
 	for _, proposal := range resGov.Proposals {
 		propID, err := strconv.ParseInt(proposal.Id, 10, 64)
 		if err != nil {
 			fmt.Println("Error:", err)
-			return nil, err
+			return nil, types.NewUpstreamError(p.terraLcdUrl, err)
 		}
 		proposalIDs = append(proposalIDs, propID)
 	}
@@ -554,20 +554,20 @@ func (p allianceValidatorsProvider) queryStation(propId int64) (res *[]types.Sta
 	// Send GET request
 	resp, err := http.Get(url)
 	if err != nil {
-		return nil, err
+		return nil, types.NewUpstreamError(p.stationApiUrl, err)
 	}
 	defer resp.Body.Close()
 
 	// Read response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, types.NewUpstreamError(p.stationApiUrl, err)
 	}
 
 	// Parse JSON response into struct
 	err = json.Unmarshal(body, &res)
 	if err != nil {
-		return nil, err
+		return nil, types.NewUpstreamError(p.stationApiUrl, err)
 	}
 
 	// Access parsed data
@@ -0,0 +1,77 @@
+package astroport
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/provider/internal"
+)
+
+// AstroportProvider prices pairs by querying the Astroport pair
+// contracts directly instead of going through the router API.
+type AstroportProvider struct {
+	*internal.WasmProvider
+	config *config.ProviderConfig
+}
+
+type simulationResponse struct {
+	ReturnAmount     string `json:"return_amount"`
+	SpreadAmount     string `json:"spread_amount"`
+	CommissionAmount string `json:"commission_amount"`
+}
+
+func NewAstroportProvider(providerConfig *config.ProviderConfig, stopCh <-chan struct{}) (*AstroportProvider, error) {
+	provider := &AstroportProvider{config: providerConfig}
+	wasmProvider, err := internal.NewWasmProvider("astroport", config.PHOENIX_GRPC, providerConfig, provider.fetchPrice, stopCh)
+	if err != nil {
+		return nil, err
+	}
+	provider.WasmProvider = wasmProvider
+	return provider, nil
+}
+
+// fetchPrice returns the price of the base coin in quote coin and its price impact,
+// either by simulating a swap of the trade size or from the reserves of the pair.
+func (p *AstroportProvider) fetchPrice(querier *internal.WasmQuerier, pool config.PoolConfig) (float64, float64, error) {
+	switch pool.Query {
+	case "", "simulation":
+		tradeSize := 1.0
+		if size, ok := p.config.TradeSizes[pool.Pair]; ok && size > 0 {
+			tradeSize = size
+		}
+		query, err := internal.SimulationQuery(pool, tradeSize, "terra1")
+		if err != nil {
+			return 0, 0, err
+		}
+		var res simulationResponse
+		if err := querier.QuerySmartContract(pool.Id, query, &res); err != nil {
+			return 0, 0, err
+		}
+		returnAmount, err := strconv.ParseFloat(res.ReturnAmount, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		// the commission is a fee of the swap, not part of the price
+		commissionAmount, err := strconv.ParseFloat(res.CommissionAmount, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		// the spread is what the swap loses to the price impact
+		spreadAmount, err := strconv.ParseFloat(res.SpreadAmount, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		amount := returnAmount + commissionAmount
+		if amount <= 0 {
+			return 0, 0, fmt.Errorf("%w for %s in the pair", internal.ErrInsufficientLiquidity, pool.Pair)
+		}
+		return amount / math.Pow10(pool.QuoteExponent) / tradeSize, spreadAmount / (amount + spreadAmount), nil
+	case "pool":
+		price, err := internal.PoolPrice(querier, pool)
+		return price, 0, err
+	default:
+		return 0, 0, fmt.Errorf("unknown query %s", pool.Query)
+	}
+}
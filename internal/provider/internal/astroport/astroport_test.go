@@ -0,0 +1,119 @@
+package astroport_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/provider/internal/astroport"
+	"github.com/terra-money/oracle-feeder-go/internal/provider/internal/wasmtest"
+)
+
+const (
+	ampLuna = "terra1ecgazyd0waaj3g7l9cmy5gulhxkps2gmxu9ghducvuypjq68mq2s5lvsct"
+	stLuna  = "ibc/08095CEDEA29977C9DD0CE9A48329FDA622C183359D5F90CF04CC4FF80CBE431"
+)
+
+// answer the queries of four pairs: a simulation pair,
+// a drained simulation pair, a pool pair and a broken pair.
+func answer(contract string, query map[string]json.RawMessage) (string, error) {
+	switch {
+	case (contract == "terra1simulation" || contract == "terra1drained") && query["simulation"] != nil:
+		var simulation struct {
+			OfferAsset struct {
+				Info   map[string]map[string]string `json:"info"`
+				Amount string                       `json:"amount"`
+			} `json:"offer_asset"`
+		}
+		if err := json.Unmarshal(query["simulation"], &simulation); err != nil {
+			return "", err
+		}
+		amount, err := strconv.ParseInt(simulation.OfferAsset.Amount, 10, 64)
+		if err != nil || simulation.OfferAsset.Info["token"]["contract_addr"] != ampLuna {
+			return "", fmt.Errorf("unexpected offer %s", string(query["simulation"]))
+		}
+		// about 1% of the swap is lost to the spread, half of it in the drained pair
+		spread := amount * 15 / 1000
+		if contract == "terra1drained" {
+			spread = amount * 3 / 2
+		}
+		return fmt.Sprintf(`{"return_amount":"%d","spread_amount":"%d","commission_amount":"%d"}`, amount*148/100, spread, amount*2/100), nil
+	case contract == "terra1pool" && query["pool"] != nil:
+		return `{"assets":[
+			{"info":{"native_token":{"denom":"uluna"}},"amount":"1200000000"},
+			{"info":{"native_token":{"denom":"` + stLuna + `"}},"amount":"1000000000"}
+		],"total_share":"1000000"}`, nil
+	default:
+		return "", fmt.Errorf("contract %s not found", contract)
+	}
+}
+
+func TestAstroportProvider(t *testing.T) {
+	// GIVEN
+	cfg := &config.ProviderConfig{
+		Interval:       1,
+		Timeout:        1,
+		Urls:           []string{wasmtest.StartNode(t, answer)},
+		TradeSizes:     map[string]float64{"AMPLUNA/LUNA": 1000},
+		MaxPriceImpact: 0.05,
+		Pools: []config.PoolConfig{
+			{Pair: "AMPLUNA/LUNA", Id: "terra1simulation", BaseDenom: ampLuna, BaseExponent: 6, QuoteDenom: "uluna", QuoteExponent: 6},
+			{Pair: "STLUNA/LUNA", Id: "terra1pool", BaseDenom: stLuna, BaseExponent: 6, QuoteDenom: "uluna", QuoteExponent: 6, Query: "pool"},
+			{Pair: "AMPLUNA/USDC", Id: "terra1drained", BaseDenom: ampLuna, BaseExponent: 6, QuoteDenom: "uusdc", QuoteExponent: 6},
+			{Pair: "BACKBONELUNA/LUNA", Id: "terra1broken", BaseDenom: "terra1backbone", BaseExponent: 6, QuoteDenom: "uluna", QuoteExponent: 6},
+		},
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	// WHEN
+	provider, err := astroport.NewAstroportProvider(cfg, stopCh)
+	require.NoError(t, err)
+
+	// THEN the broken pair doesn't prevent the others from being priced
+	// and the drained pair is rejected for its price impact
+	prices := wasmtest.WaitForPrices(t, provider, 2)
+	require.InDelta(t, 1.5, prices["AMPLUNA/LUNA"].Price, 1e-9)
+	require.InDelta(t, 0.01, prices["AMPLUNA/LUNA"].PriceImpact, 1e-3)
+	require.InDelta(t, 1.2, prices["STLUNA/LUNA"].Price, 1e-9)
+	require.Zero(t, prices["STLUNA/LUNA"].PriceImpact)
+	require.NotContains(t, prices, "AMPLUNA/USDC")
+}
+
+func TestAstroportProviderStalePrice(t *testing.T) {
+	// GIVEN a pool priced once then failing
+	var failing atomic.Bool
+	node := wasmtest.StartNode(t, func(contract string, query map[string]json.RawMessage) (string, error) {
+		if failing.Load() {
+			return "", fmt.Errorf("node unavailable")
+		}
+		return answer(contract, query)
+	})
+	cfg := &config.ProviderConfig{
+		Interval:    1,
+		Timeout:     1,
+		Urls:        []string{node},
+		MaxStaleAge: 2,
+		Pools: []config.PoolConfig{
+			{Pair: "STLUNA/LUNA", Id: "terra1pool", BaseDenom: stLuna, BaseExponent: 6, QuoteDenom: "uluna", QuoteExponent: 6, Query: "pool"},
+		},
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	provider, err := astroport.NewAstroportProvider(cfg, stopCh)
+	require.NoError(t, err)
+	require.False(t, wasmtest.WaitForPrices(t, provider, 1)["STLUNA/LUNA"].Stale)
+
+	// WHEN
+	failing.Store(true)
+
+	// THEN its last price is served as stale, then withdrawn after the max stale age
+	require.Eventually(t, func() bool { return provider.GetPrices()["STLUNA/LUNA"].Stale }, 3*time.Second, 10*time.Millisecond)
+	require.InDelta(t, 1.2, provider.GetPrices()["STLUNA/LUNA"].Price, 1e-9)
+	wasmtest.WaitForPrices(t, provider, 0)
+}
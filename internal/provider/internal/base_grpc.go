@@ -9,6 +9,7 @@ import (
 	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
 	govtypesv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
 	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	"github.com/terra-money/oracle-feeder-go/internal/metrics"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
@@ -28,7 +29,7 @@ func (p *BaseGrpc) Connection(
 	authCredentials := grpc.WithTransportCredentials(insecure.NewCredentials())
 	callOptions := grpc.WithDefaultCallOptions()
 
-	if strings.Contains(nodeUrl, ":443") {
+	if strings.HasSuffix(nodeUrl, ":443") {
 		authCredentials = grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{}))
 	}
 
@@ -41,6 +42,7 @@ func (p *BaseGrpc) Connection(
 		nodeUrl,
 		authCredentials,
 		callOptions,
+		grpc.WithUnaryInterceptor(metrics.UnaryClientInterceptor(nodeUrl)),
 	)
 }
 
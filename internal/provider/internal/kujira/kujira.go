@@ -0,0 +1,136 @@
+package kujira
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/provider/internal"
+)
+
+// number of price levels read on each side of the book
+const bookLimit = 30
+
+// KujiraProvider prices pairs from the order books of the Kujira FIN contracts.
+type KujiraProvider struct {
+	*internal.WasmProvider
+	config *config.ProviderConfig
+}
+
+// see https://docs.kujira.app/developers/smart-contracts/fin
+type bookResponse struct {
+	Base  []bookLevel `json:"base"`  // orders offering the base coin, i.e. asks
+	Quote []bookLevel `json:"quote"` // orders offering the quote coin, i.e. bids
+}
+
+type bookLevel struct {
+	QuotePrice       string `json:"quote_price"`
+	TotalOfferAmount string `json:"total_offer_amount"`
+}
+
+// level is a price level with its amount in base coin.
+type level struct {
+	price  float64
+	amount float64
+}
+
+func NewKujiraProvider(providerConfig *config.ProviderConfig, stopCh <-chan struct{}) (*KujiraProvider, error) {
+	provider := &KujiraProvider{config: providerConfig}
+	wasmProvider, err := internal.NewWasmProvider("kujira", config.KUJIRA_GRPC, providerConfig, provider.fetchPrice, stopCh)
+	if err != nil {
+		return nil, err
+	}
+	provider.WasmProvider = wasmProvider
+	return provider, nil
+}
+
+// fetchPrice returns the mid price between the average prices of buying and
+// selling the trade size through the book, and the price impact of doing so.
+// Without trade size the mid price is taken between the best bid and ask.
+func (p *KujiraProvider) fetchPrice(querier *internal.WasmQuerier, pool config.PoolConfig) (float64, float64, error) {
+	query, err := json.Marshal(map[string]interface{}{
+		"book": map[string]interface{}{"limit": bookLimit},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	var res bookResponse
+	if err := querier.QuerySmartContract(pool.Id, query, &res); err != nil {
+		return 0, 0, err
+	}
+
+	// prices are quoted in the smallest units, e.g. uusdc per ukuji
+	priceFactor := math.Pow10(pool.BaseExponent - pool.QuoteExponent)
+	asks, err := parseLevels(res.Base, priceFactor, math.Pow10(pool.BaseExponent), false)
+	if err != nil {
+		return 0, 0, err
+	}
+	bids, err := parseLevels(res.Quote, priceFactor, math.Pow10(pool.QuoteExponent), true)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(asks) == 0 || len(bids) == 0 {
+		return 0, 0, fmt.Errorf("%w: empty book", internal.ErrInsufficientLiquidity)
+	}
+	sort.Slice(asks, func(i, j int) bool { return asks[i].price < asks[j].price })
+	sort.Slice(bids, func(i, j int) bool { return bids[i].price > bids[j].price })
+
+	tradeSize := p.config.TradeSizes[pool.Pair]
+	ask, err := averagePrice(asks, tradeSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("asks: %w", err)
+	}
+	bid, err := averagePrice(bids, tradeSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("bids: %w", err)
+	}
+	mid := (ask + bid) / 2
+	return mid, (ask - mid) / mid, nil
+}
+
+// parseLevels converts the book levels to prices in quote coin per
+// base coin and amounts in base coin, bids offer the quote coin.
+func parseLevels(levels []bookLevel, priceFactor, amountFactor float64, bids bool) ([]level, error) {
+	var result []level
+	for _, l := range levels {
+		price, err := strconv.ParseFloat(l.QuotePrice, 64)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := strconv.ParseFloat(l.TotalOfferAmount, 64)
+		if err != nil {
+			return nil, err
+		}
+		if price <= 0 || amount <= 0 {
+			continue
+		}
+		price *= priceFactor
+		amount /= amountFactor
+		if bids {
+			amount /= price
+		}
+		result = append(result, level{price: price, amount: amount})
+	}
+	return result, nil
+}
+
+// averagePrice returns the average price of trading the size
+// through the sorted levels, or the best price when size is zero.
+func averagePrice(levels []level, size float64) (float64, error) {
+	if size <= 0 {
+		return levels[0].price, nil
+	}
+	var filled, cost float64
+	for _, l := range levels {
+		amount := math.Min(l.amount, size-filled)
+		filled += amount
+		cost += amount * l.price
+		if filled >= size {
+			return cost / filled, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: depth of %f is lower than the trade size %f", internal.ErrInsufficientLiquidity, filled, size)
+}
@@ -0,0 +1,79 @@
+package kujira_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/provider/internal/kujira"
+	"github.com/terra-money/oracle-feeder-go/internal/provider/internal/wasmtest"
+)
+
+// book answers the book of a FIN contract with asks of 100 KUJI at 0.80 and
+// 200 KUJI at 0.82, and bids of 100 KUJI at 0.78 and 200 KUJI at 0.75, the
+// amounts of the bids being in USDC. A shallow book only has the best levels.
+func book(shallow *atomic.Bool) wasmtest.QueryFunc {
+	return func(contract string, query map[string]json.RawMessage) (string, error) {
+		if contract != "kujira1fin" || string(query["book"]) != `{"limit":30}` {
+			return "", fmt.Errorf("unexpected query %v on %s", query, contract)
+		}
+		if shallow.Load() {
+			return `{
+				"base":[{"quote_price":"0.8","offer_denom":{"native":"ukuji"},"total_offer_amount":"100000000"}],
+				"quote":[{"quote_price":"0.78","offer_denom":{"native":"uusdc"},"total_offer_amount":"78000000"}]
+			}`, nil
+		}
+		return `{
+			"base":[
+				{"quote_price":"0.82","offer_denom":{"native":"ukuji"},"total_offer_amount":"200000000"},
+				{"quote_price":"0.8","offer_denom":{"native":"ukuji"},"total_offer_amount":"100000000"}
+			],
+			"quote":[
+				{"quote_price":"0.78","offer_denom":{"native":"uusdc"},"total_offer_amount":"78000000"},
+				{"quote_price":"0.75","offer_denom":{"native":"uusdc"},"total_offer_amount":"150000000"}
+			]
+		}`, nil
+	}
+}
+
+func TestKujiraProvider(t *testing.T) {
+	// GIVEN the same book read with different trade sizes
+	var shallow atomic.Bool
+	cfg := &config.ProviderConfig{
+		Interval:   1,
+		Timeout:    1,
+		Urls:       []string{wasmtest.StartNode(t, book(&shallow))},
+		TradeSizes: map[string]float64{"KUJI/USDC": 200, "KUJI/USK": 250, "KUJI/EUR": 1000},
+		Pools: []config.PoolConfig{
+			{Pair: "KUJI/AXLUSDC", Id: "kujira1fin", BaseExponent: 6, QuoteExponent: 6},
+			{Pair: "KUJI/USDC", Id: "kujira1fin", BaseExponent: 6, QuoteExponent: 6},
+			{Pair: "KUJI/USK", Id: "kujira1fin", BaseExponent: 6, QuoteExponent: 6},
+			{Pair: "KUJI/EUR", Id: "kujira1fin", BaseExponent: 6, QuoteExponent: 6},
+		},
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	// WHEN
+	provider, err := kujira.NewKujiraProvider(cfg, stopCh)
+	require.NoError(t, err)
+
+	// THEN the book is too shallow for the last trade size
+	prices := wasmtest.WaitForPrices(t, provider, 3)
+	require.InDelta(t, 0.79, prices["KUJI/AXLUSDC"].Price, 1e-9)
+	require.InDelta(t, 0.01/0.79, prices["KUJI/AXLUSDC"].PriceImpact, 1e-9)
+	require.InDelta(t, (0.81+0.765)/2, prices["KUJI/USDC"].Price, 1e-9)
+	require.InDelta(t, (0.812+0.762)/2, prices["KUJI/USK"].Price, 1e-9)
+	require.NotContains(t, prices, "KUJI/EUR")
+
+	// WHEN the book gets shallower than the priced trade sizes
+	shallow.Store(true)
+
+	// THEN their prices are withdrawn rather than served again
+	prices = wasmtest.WaitForPrices(t, provider, 1)
+	require.InDelta(t, 0.79, prices["KUJI/AXLUSDC"].Price, 1e-9)
+	require.False(t, prices["KUJI/AXLUSDC"].Stale)
+}
@@ -1,74 +1,56 @@
 package osmosis
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
+	"math"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
 	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/metrics"
+	"github.com/terra-money/oracle-feeder-go/internal/provider/internal"
+	"github.com/terra-money/oracle-feeder-go/internal/reporting"
 	internal_types "github.com/terra-money/oracle-feeder-go/internal/types"
 	"github.com/terra-money/oracle-feeder-go/pkg/types"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
 )
 
-type OsmosisEndpoint struct {
-	url  string
-	used bool
-}
+const (
+	// see https://github.com/osmosis-labs/osmosis/blob/main/proto/osmosis/poolmanager/v1beta1/query.proto
+	spotPriceMethod = "/osmosis.poolmanager.v1beta1.Query/SpotPrice"
+	// see https://github.com/osmosis-labs/osmosis/blob/main/proto/osmosis/twap/v1beta1/query.proto
+	twapMethod = "/osmosis.twap.v1beta1.Query/ArithmeticTwapToNow"
+)
 
 type OsmosisProvider struct {
+	internal.BaseGrpc
 	priceBySymbol map[string]internal_types.PriceBySymbol
 	config        *config.ProviderConfig
 	mu            *sync.Mutex
 }
 
-var endpoints = []OsmosisEndpoint{{
-	url:  "https://osmosis-api.polkachu.com/osmosis/gamm/v1beta1/pools/${POOL_ID}",
-	used: false,
-}, {
-	url:  "https://osmosis-api.polkachu.com/osmosis/gamm/v1beta1/pools/${POOL_ID}",
-	used: false,
-}, {
-	url:  "https://lcd-osmosis.tfl.foundation/osmosis/gamm/v1beta1/pools/${POOL_ID}",
-	used: false,
-}}
-
-var whiteListPoolIds = map[string]string{
-	"ATOM/OSMO":  "1",
-	"AKT/OSMO":   "3",
-	"JUNO/OSMO":  "497",
-	"SCRT/OSMO":  "584",
-	"STARS/OSMO": "604",
-	"USDC/OSMO":  "678",
-	"INJ/OSMO":   "725",
-	"LUNA/OSMO":  "726",
-	"KAVA/OSMO":  "730",
-	"LINK/OSMO":  "731",
-	"LUNC/OSMO":  "800",
-	"ASH/USDC":   "1360",
-	"OSMO/USDC":  "1464",
-}
-var idsBySymbol = make(map[string]string)
-
 func NewOsmosisProvider(config *config.ProviderConfig, stopCh <-chan struct{}) (*OsmosisProvider, error) {
+	if len(config.Urls) == 0 {
+		return nil, fmt.Errorf("osmosis: no gRPC url configured")
+	}
+
 	mu := sync.Mutex{}
 	provider := &OsmosisProvider{
+		BaseGrpc:      *internal.NewBaseGrpc(),
 		priceBySymbol: make(map[string]internal_types.PriceBySymbol),
 		config:        config,
 		mu:            &mu,
 	}
 
-	for k, v := range whiteListPoolIds {
-		idsBySymbol[v] = k
-	}
-
 	go func() {
-		ticker := time.NewTicker(time.Duration(config.Interval) * time.Second)
+		defer reporting.Recover()
+		ticker := time.NewTicker(config.PollInterval())
 		provider.fetchAndParse()
 		for {
 			select {
@@ -101,105 +83,172 @@ func (p *OsmosisProvider) GetPrices() map[string]types.PriceByPair {
 }
 
 func (p *OsmosisProvider) fetchAndParse() {
-	for id, symbol := range idsBySymbol {
-		res, err := fetchPrice(id)
+	var conns []*grpc.ClientConn
+	for _, url := range p.config.Urls {
+		conn, err := p.BaseGrpc.Connection(context.Background(), url)
 		if err != nil {
-			log.Printf("%v", err)
+			log.Printf("osmosis %s: %v", url, err)
+			reporting.CaptureError(err, map[string]string{"exchange": "osmosis"})
 			continue
 		}
-		var generic internal_types.GenericPoolResponse
-		if err := json.Unmarshal(res, &generic); err != nil {
-			fmt.Println("Error:", err)
+		defer conn.Close()
+		conns = append(conns, conn)
+	}
+
+	for _, pool := range p.config.Pools {
+		pair := strings.Split(pool.Pair, "/")
+		if len(pair) != 2 {
+			log.Printf("osmosis: invalid pair %s", pool.Pair)
 			continue
 		}
-		price, err := p.parsePrice(generic, res)
+		price, err := p.fetchPrice(conns, pool)
 		if err != nil {
+			log.Printf("osmosis pool %s: %v", pool.Id, err)
+			metrics.ProviderFailed("osmosis")
 			continue
 		}
 
 		p.mu.Lock()
-		p.priceBySymbol[symbol] = internal_types.PriceBySymbol{
-			Symbol:    symbol,
+		p.priceBySymbol[pool.Pair] = internal_types.PriceBySymbol{
+			Exchange:  "osmosis",
+			Symbol:    pool.Id,
+			Base:      pair[0],
+			Quote:     pair[1],
 			Price:     price,
-			Base:      strings.Split(symbol, "/")[0],
-			Quote:     strings.Split(symbol, "/")[1],
-			Timestamp: uint64(time.Now().Unix()),
+			Timestamp: uint64(time.Now().UnixMilli()),
 		}
 		p.mu.Unlock()
+		metrics.ProviderUpdated("osmosis")
 	}
 }
 
-func (*OsmosisProvider) parsePrice(generic internal_types.GenericPoolResponse, res []byte) (float64, error) {
-	switch generic.Pool.Type {
-	case "/osmosis.concentratedliquidity.v1beta1.Pool":
-		var pool internal_types.OsmosisPoolResponse
-		if err := json.Unmarshal(res, &pool); err != nil {
-			return 0, err
-		}
+// fetchPrice returns the price of the base coin of the pool in quote coin,
+// either from the current pool state or from the TWAP module.
+func (p *OsmosisProvider) fetchPrice(conns []*grpc.ClientConn, pool config.PoolConfig) (float64, error) {
+	poolId, err := strconv.ParseUint(pool.Id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid pool id %s", pool.Id)
+	}
 
-		// get the first 18 positons of the price to avoid overflow
-		price := pool.Pool.CurrentSqrtPrice
-		if len(price) >= 18 {
-			price = pool.Pool.CurrentSqrtPrice[:18]
-		}
-		parsedPrice, err := sdktypes.NewDecFromStr(price)
+	var price float64
+	if p.config.TwapWindow > 0 {
+		startTime := time.Now().Add(-time.Duration(p.config.TwapWindow) * time.Second)
+		res, err := p.invoke(conns, twapMethod, twapRequest(poolId, pool.BaseDenom, pool.QuoteDenom, startTime))
 		if err != nil {
 			return 0, err
 		}
-		return parsedPrice.Power(2).Float64()
-	case "/osmosis.gamm.v1beta1.Pool":
-		var pool internal_types.OsmosisGammPoolResponse
-		if err := json.Unmarshal(res, &pool); err != nil {
+		// arithmetic_twap is a sdk.Dec, encoded as its integer representation
+		field := stringField(res, 1)
+		if len(field) == 0 {
+			return 0, fmt.Errorf("no TWAP in response")
+		}
+		var twap sdktypes.Dec
+		if err := twap.Unmarshal(field); err != nil {
 			return 0, err
 		}
-
-		// get the first 18 positons of the price to avoid overflow
-		firstTokenPrice := pool.Pool.PoolAssets[0].Token.Amount
-		if len(firstTokenPrice) >= 18 {
-			firstTokenPrice = firstTokenPrice[:18]
+		if price, err = twap.Float64(); err != nil {
+			return 0, err
 		}
-		parsedFirstTokenPrice, err := sdktypes.NewDecFromStr(firstTokenPrice)
+	} else {
+		res, err := p.invoke(conns, spotPriceMethod, spotPriceRequest(poolId, pool.BaseDenom, pool.QuoteDenom))
 		if err != nil {
 			return 0, err
 		}
-		secondTokenPrice := pool.Pool.PoolAssets[1].Token.Amount
-		if len(secondTokenPrice) >= 18 {
-			secondTokenPrice = secondTokenPrice[:18]
-		}
-		parsedSecondTokenPrice, err := sdktypes.NewDecFromStr(secondTokenPrice)
-		if err != nil {
+		if price, err = strconv.ParseFloat(string(stringField(res, 1)), 64); err != nil {
 			return 0, err
 		}
-		return parsedSecondTokenPrice.Quo(parsedFirstTokenPrice).Float64()
-	default:
-		return 0, fmt.Errorf("unknown pool type: %s", generic.Pool.Type)
 	}
+	if price <= 0 {
+		return 0, fmt.Errorf("invalid price %f", price)
+	}
+
+	// on-chain prices are quoted in the smallest units, e.g. uosmo per uatom
+	return price * math.Pow10(pool.BaseExponent-pool.QuoteExponent), nil
 }
 
-func fetchPrice(poolId string) (res []byte, err error) {
-	url, err := rotateUrl()
-	if err != nil {
-		return nil, err
+// invoke sends the query to the first node that answers.
+func (p *OsmosisProvider) invoke(conns []*grpc.ClientConn, method string, req []byte) ([]byte, error) {
+	timeout := p.config.Timeout
+	if timeout == 0 {
+		timeout = 10
 	}
-	client := &http.Client{Timeout: time.Second * 15}
-	resp, err := client.Get(strings.Replace(url, "${POOL_ID}", poolId, 1))
-	if err != nil {
-		return nil, err
+
+	var errs []string
+	for _, conn := range conns {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+		var res []byte
+		err := conn.Invoke(ctx, method, &req, &res, grpc.ForceCodec(rawCodec{}))
+		cancel()
+		if err == nil {
+			return res, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", conn.Target(), err))
 	}
+	return nil, fmt.Errorf("%s failed on every node: %s", method, strings.Join(errs, "; "))
+}
+
+// rawCodec sends and receives already encoded protobuf messages, the few
+// Osmosis queries are encoded by hand to avoid depending on the Osmosis module.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	return *v.(*[]byte), nil
+}
 
-	return io.ReadAll(resp.Body)
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	*v.(*[]byte) = data
+	return nil
 }
 
-func rotateUrl() (string, error) {
-	for i := range endpoints {
-		if !endpoints[i].used {
-			endpoints[i].used = true
-			return endpoints[i].url, nil
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// spotPriceRequest encodes a SpotPriceRequest:
+// {pool_id = 1, base_asset_denom = 2, quote_asset_denom = 3}
+func spotPriceRequest(poolId uint64, baseDenom, quoteDenom string) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, poolId)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendString(b, baseDenom)
+	b = protowire.AppendTag(b, 3, protowire.BytesType)
+	b = protowire.AppendString(b, quoteDenom)
+	return b
+}
+
+// twapRequest encodes an ArithmeticTwapToNowRequest:
+// {pool_id = 1, base_asset = 2, quote_asset = 3, start_time = 4}
+func twapRequest(poolId uint64, baseDenom, quoteDenom string, startTime time.Time) []byte {
+	var timestamp []byte
+	timestamp = protowire.AppendTag(timestamp, 1, protowire.VarintType)
+	timestamp = protowire.AppendVarint(timestamp, uint64(startTime.Unix()))
+	timestamp = protowire.AppendTag(timestamp, 2, protowire.VarintType)
+	timestamp = protowire.AppendVarint(timestamp, uint64(startTime.Nanosecond()))
+
+	b := spotPriceRequest(poolId, baseDenom, quoteDenom)
+	b = protowire.AppendTag(b, 4, protowire.BytesType)
+	b = protowire.AppendBytes(b, timestamp)
+	return b
+}
+
+// stringField returns the value of a string field of an encoded message.
+func stringField(msg []byte, field protowire.Number) []byte {
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return nil
 		}
+		msg = msg[n:]
+		if num == field && typ == protowire.BytesType {
+			value, _ := protowire.ConsumeBytes(msg)
+			return value
+		}
+		n = protowire.ConsumeFieldValue(num, typ, msg)
+		if n < 0 {
+			return nil
+		}
+		msg = msg[n:]
 	}
-	for i := range endpoints {
-		endpoints[i].used = false
-	}
-	endpoints[0].used = true
-	return endpoints[0].url, nil
+	return nil
 }
@@ -0,0 +1,113 @@
+package osmosis_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/provider/internal/osmosis"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	uatom = "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2"
+	inj   = "ibc/64BA6E31FE887D66C6F8F31C7B1A80C7CA179239677B4088BB55F5EA07DBE273"
+)
+
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) { return *v.(*[]byte), nil }
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	*v.(*[]byte) = data
+	return nil
+}
+func (rawCodec) Name() string { return "proto" }
+
+// startNode starts an Osmosis gRPC node where, in the smallest units,
+// 1 uatom is worth 8 uosmo and 1 inj (1e-18 INJ) is worth 5e-12 uosmo.
+func startNode(t *testing.T) string {
+	spotPrices := map[string]string{
+		uatom: "8.000000000000000000",
+		inj:   "0.000000000005000000",
+	}
+	// TWAP prices are sdk.Dec encoded as integers with 18 decimals
+	twapPrices := map[string]string{
+		uatom: "7500000000000000000",
+	}
+
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		method, _ := grpc.MethodFromServerStream(stream)
+		var req []byte
+		if err := stream.RecvMsg(&req); err != nil {
+			return err
+		}
+		// base_asset_denom is the second field, after the pool id
+		_, _, n := protowire.ConsumeTag(req)
+		_, m := protowire.ConsumeVarint(req[n:])
+		req = req[n+m:]
+		_, _, n = protowire.ConsumeTag(req)
+		denom, _ := protowire.ConsumeString(req[n:])
+
+		prices := spotPrices
+		if method == "/osmosis.twap.v1beta1.Query/ArithmeticTwapToNow" {
+			prices = twapPrices
+		}
+		var res []byte
+		res = protowire.AppendTag(res, 1, protowire.BytesType)
+		res = protowire.AppendString(res, prices[denom])
+		return stream.SendMsg(&res)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(grpc.ForceServerCodec(rawCodec{}), grpc.UnknownServiceHandler(handler))
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func newConfig(t *testing.T) *config.ProviderConfig {
+	// GIVEN a dead node followed by a working one
+	return &config.ProviderConfig{
+		Interval: 1,
+		Timeout:  1,
+		Urls:     []string{"127.0.0.1:1", startNode(t)},
+		Pools: []config.PoolConfig{
+			{Pair: "ATOM/OSMO", Id: "1", BaseDenom: uatom, BaseExponent: 6, QuoteDenom: "uosmo", QuoteExponent: 6},
+			{Pair: "INJ/OSMO", Id: "725", BaseDenom: inj, BaseExponent: 18, QuoteDenom: "uosmo", QuoteExponent: 6},
+		},
+	}
+}
+
+func TestOsmosisProviderSpotPrice(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	// WHEN
+	provider, err := osmosis.NewOsmosisProvider(newConfig(t), stopCh)
+	require.NoError(t, err)
+
+	// THEN
+	require.Eventually(t, func() bool { return len(provider.GetPrices()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	prices := provider.GetPrices()
+	require.InDelta(t, 8, prices["ATOM/OSMO"].Price, 1e-9)
+	require.InDelta(t, 5, prices["INJ/OSMO"].Price, 1e-9)
+}
+
+func TestOsmosisProviderTwap(t *testing.T) {
+	cfg := newConfig(t)
+	cfg.TwapWindow = 300
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	// WHEN
+	provider, err := osmosis.NewOsmosisProvider(cfg, stopCh)
+	require.NoError(t, err)
+
+	// THEN the pool without TWAP is skipped
+	require.Eventually(t, func() bool { return len(provider.GetPrices()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.InDelta(t, 7.5, provider.GetPrices()["ATOM/OSMO"].Price, 1e-9)
+}
@@ -2,13 +2,16 @@ package internal
 
 import (
 	"fmt"
+	"log"
 	"sync"
 	"time"
 
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/metrics"
+	"github.com/terra-money/oracle-feeder-go/internal/reporting"
 	"github.com/terra-money/oracle-feeder-go/internal/restful"
 	internal_types "github.com/terra-money/oracle-feeder-go/internal/types"
 	"github.com/terra-money/oracle-feeder-go/pkg/types"
-	"golang.org/x/exp/maps"
 )
 
 type RESTfulProvider struct {
@@ -16,8 +19,8 @@ type RESTfulProvider struct {
 	mu            *sync.Mutex
 }
 
-func NewRESTfulProvider(exchange string, symbols []string, interval int, timeout int, stopCh <-chan struct{}) (*RESTfulProvider, error) {
-	client, err := restful.NewRESTfulClient(exchange, symbols)
+func NewRESTfulProvider(exchange string, config *config.ProviderConfig, stopCh <-chan struct{}) (*RESTfulProvider, error) {
+	client, err := restful.NewRESTfulClient(exchange, config)
 	if err != nil {
 		return nil, err
 	}
@@ -28,26 +31,29 @@ func NewRESTfulProvider(exchange string, symbols []string, interval int, timeout
 		mu:            &mu,
 	}
 
-	go func() {
-		ticker := time.NewTicker(time.Duration(interval) * time.Second)
-		prices, err := client.FetchAndParse(symbols, timeout)
-		if err == nil {
-			mu.Lock()
-			maps.Copy(provider.priceBySymbol, prices)
-			mu.Unlock()
+	fetchAndParse := func() {
+		prices, err := client.FetchAndParse(config.Symbols, config.Timeout)
+		if err != nil {
+			log.Printf("%s: %v", exchange, err)
+			metrics.ProviderFailed(exchange)
+			reporting.CaptureError(err, map[string]string{"exchange": exchange})
+			return
 		}
+		metrics.ProviderUpdated(exchange)
+		provider.update(prices)
+	}
+
+	go func() {
+		defer reporting.Recover()
+		ticker := time.NewTicker(config.PollInterval())
+		fetchAndParse()
 		for {
 			select {
 			case <-stopCh:
 				ticker.Stop()
 				return
 			case <-ticker.C:
-				prices, err := client.FetchAndParse(symbols, timeout)
-				if err == nil {
-					mu.Lock()
-					maps.Copy(provider.priceBySymbol, prices)
-					mu.Unlock()
-				}
+				fetchAndParse()
 			}
 		}
 	}()
@@ -55,6 +61,20 @@ func NewRESTfulProvider(exchange string, symbols []string, interval int, timeout
 	return provider, nil
 }
 
+// update stores the fetched prices, clients withdraw a
+// symbol they can no longer price by returning a zero price.
+func (p *RESTfulProvider) update(prices map[string]internal_types.PriceBySymbol) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for symbol, price := range prices {
+		if price.Price > 0 {
+			p.priceBySymbol[symbol] = price
+		} else {
+			delete(p.priceBySymbol, symbol)
+		}
+	}
+}
+
 func (p *RESTfulProvider) GetPrices() map[string]types.PriceByPair {
 	result := make(map[string]types.PriceByPair)
 	p.mu.Lock()
@@ -62,10 +82,12 @@ func (p *RESTfulProvider) GetPrices() map[string]types.PriceByPair {
 	for _, price := range p.priceBySymbol {
 		pair := fmt.Sprintf("%s/%s", price.Base, price.Quote)
 		result[pair] = types.PriceByPair{
-			Base:      price.Base,
-			Quote:     price.Quote,
-			Price:     price.Price,
-			Timestamp: price.Timestamp,
+			Base:        price.Base,
+			Quote:       price.Quote,
+			Price:       price.Price,
+			Timestamp:   price.Timestamp,
+			PriceImpact: price.PriceImpact,
+			Stale:       price.Stale,
 		}
 	}
 	return result
@@ -0,0 +1,327 @@
+package uniswap
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/metrics"
+	"github.com/terra-money/oracle-feeder-go/internal/reporting"
+	internal_types "github.com/terra-money/oracle-feeder-go/internal/types"
+	"github.com/terra-money/oracle-feeder-go/pkg/types"
+)
+
+const (
+	exchange = "uniswap"
+
+	// function selectors, the first 4 bytes of keccak256(signature)
+	slot0Selector    = "0x3850c7bd" // slot0()
+	token0Selector   = "0x0dfe1681" // token0()
+	token1Selector   = "0xd21220a7" // token1()
+	decimalsSelector = "0x313ce567" // decimals()
+)
+
+// UniswapProvider prices pairs from the state of Uniswap v3 style
+// pools (Uniswap, PancakeSwap v3, Sushi v3, ...) read over EVM JSON-RPC.
+type UniswapProvider struct {
+	priceBySymbol map[string]internal_types.PriceBySymbol
+	config        *config.ProviderConfig
+	client        *http.Client
+	tokens        map[string]*poolTokens // pool address -> tokens of the pool
+	mu            *sync.Mutex
+}
+
+// poolTokens never change for a given pool so they are queried only once.
+type poolTokens struct {
+	token0    string
+	token1    string
+	decimals0 int64
+	decimals1 int64
+}
+
+func NewUniswapProvider(config *config.ProviderConfig, stopCh <-chan struct{}) (*UniswapProvider, error) {
+	if len(config.Urls) == 0 {
+		return nil, fmt.Errorf("%s: no JSON-RPC url configured", exchange)
+	}
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 10
+	}
+
+	mu := sync.Mutex{}
+	provider := &UniswapProvider{
+		priceBySymbol: make(map[string]internal_types.PriceBySymbol),
+		config:        config,
+		client:        &http.Client{Timeout: time.Duration(timeout) * time.Second},
+		tokens:        make(map[string]*poolTokens),
+		mu:            &mu,
+	}
+
+	go func() {
+		defer reporting.Recover()
+		ticker := time.NewTicker(config.PollInterval())
+		provider.fetchAndParse()
+		for {
+			select {
+			case <-stopCh:
+				ticker.Stop()
+				return
+			case <-ticker.C:
+				provider.fetchAndParse()
+			}
+		}
+	}()
+
+	return provider, nil
+}
+
+func (p *UniswapProvider) GetPrices() map[string]types.PriceByPair {
+	result := make(map[string]types.PriceByPair)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, price := range p.priceBySymbol {
+		pair := fmt.Sprintf("%s/%s", price.Base, price.Quote)
+		result[pair] = types.PriceByPair{
+			Base:      price.Base,
+			Quote:     price.Quote,
+			Price:     price.Price,
+			Timestamp: price.Timestamp,
+		}
+	}
+	return result
+}
+
+func (p *UniswapProvider) fetchAndParse() {
+	for _, pool := range p.config.Pools {
+		pair := strings.Split(pool.Pair, "/")
+		if len(pair) != 2 {
+			log.Printf("%s: invalid pair %s", exchange, pool.Pair)
+			continue
+		}
+		price, err := p.fetchPrice(pool)
+		if err != nil {
+			log.Printf("%s pool %s: %v", exchange, pool.Id, err)
+			metrics.ProviderFailed(exchange)
+			continue
+		}
+
+		p.mu.Lock()
+		p.priceBySymbol[pool.Pair] = internal_types.PriceBySymbol{
+			Exchange:  exchange,
+			Symbol:    pool.Id,
+			Base:      pair[0],
+			Quote:     pair[1],
+			Price:     price,
+			Timestamp: uint64(time.Now().UnixMilli()),
+		}
+		p.mu.Unlock()
+		metrics.ProviderUpdated(exchange)
+	}
+}
+
+// fetchPrice returns the price of the base coin of the pool in quote coin.
+func (p *UniswapProvider) fetchPrice(pool config.PoolConfig) (float64, error) {
+	tokens, err := p.poolTokens(pool.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	// slot0 returns (uint160 sqrtPriceX96, int24 tick, ...)
+	// see https://docs.uniswap.org/contracts/v3/reference/core/interfaces/pool/IUniswapV3PoolState#slot0
+	res, err := p.ethCall(pool.Id, slot0Selector)
+	if err != nil {
+		return 0, err
+	}
+	sqrtPriceX96, err := word(res, 0)
+	if err != nil {
+		return 0, err
+	}
+	if sqrtPriceX96.Sign() == 0 {
+		return 0, fmt.Errorf("pool is not initialized")
+	}
+
+	price := priceFromSqrtPriceX96(sqrtPriceX96, tokens.decimals0, tokens.decimals1)
+	inverted, err := baseIsToken1(pool, tokens)
+	if err != nil {
+		return 0, err
+	}
+	if inverted {
+		return 1 / price, nil
+	}
+	return price, nil
+}
+
+// baseIsToken1 tells which token of the pool is the base coin from the base or
+// the quote denom of the pool config, token0 being the base when neither is set.
+func baseIsToken1(pool config.PoolConfig, tokens *poolTokens) (bool, error) {
+	for _, denom := range []string{pool.BaseDenom, pool.QuoteDenom} {
+		if denom != "" && !strings.EqualFold(denom, tokens.token0) && !strings.EqualFold(denom, tokens.token1) {
+			return false, fmt.Errorf("token %s is not in the pool", denom)
+		}
+	}
+	if pool.BaseDenom != "" && strings.EqualFold(pool.BaseDenom, pool.QuoteDenom) {
+		return false, fmt.Errorf("base and quote tokens are both %s", pool.BaseDenom)
+	}
+	if pool.BaseDenom != "" {
+		return strings.EqualFold(pool.BaseDenom, tokens.token1), nil
+	}
+	return strings.EqualFold(pool.QuoteDenom, tokens.token0), nil
+}
+
+// priceFromSqrtPriceX96 returns the price of token0 in token1 adjusted by
+// the decimals of both tokens: (sqrtPriceX96 / 2^96)^2 * 10^(decimals0 - decimals1)
+func priceFromSqrtPriceX96(sqrtPriceX96 *big.Int, decimals0, decimals1 int64) float64 {
+	q96 := new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), 96))
+	sqrtPrice := new(big.Float).SetPrec(256).SetInt(sqrtPriceX96)
+	sqrtPrice.Quo(sqrtPrice, q96)
+	price := new(big.Float).SetPrec(256).Mul(sqrtPrice, sqrtPrice)
+
+	exponent := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(abs(decimals0-decimals1)), nil))
+	if decimals0 > decimals1 {
+		price.Mul(price, exponent)
+	} else {
+		price.Quo(price, exponent)
+	}
+	result, _ := price.Float64()
+	return result
+}
+
+func (p *UniswapProvider) poolTokens(poolAddress string) (*poolTokens, error) {
+	p.mu.Lock()
+	tokens, ok := p.tokens[poolAddress]
+	p.mu.Unlock()
+	if ok {
+		return tokens, nil
+	}
+
+	token0, err := p.queryAddress(poolAddress, token0Selector)
+	if err != nil {
+		return nil, err
+	}
+	token1, err := p.queryAddress(poolAddress, token1Selector)
+	if err != nil {
+		return nil, err
+	}
+	decimals0, err := p.queryUint(token0, decimalsSelector)
+	if err != nil {
+		return nil, err
+	}
+	decimals1, err := p.queryUint(token1, decimalsSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens = &poolTokens{
+		token0:    token0,
+		token1:    token1,
+		decimals0: decimals0.Int64(),
+		decimals1: decimals1.Int64(),
+	}
+	p.mu.Lock()
+	p.tokens[poolAddress] = tokens
+	p.mu.Unlock()
+	return tokens, nil
+}
+
+func (p *UniswapProvider) queryAddress(contract, selector string) (string, error) {
+	res, err := p.ethCall(contract, selector)
+	if err != nil {
+		return "", err
+	}
+	value, err := word(res, 0)
+	if err != nil {
+		return "", err
+	}
+	// addresses are right aligned in the 32 bytes word
+	return fmt.Sprintf("0x%040x", value), nil
+}
+
+func (p *UniswapProvider) queryUint(contract, selector string) (*big.Int, error) {
+	res, err := p.ethCall(contract, selector)
+	if err != nil {
+		return nil, err
+	}
+	return word(res, 0)
+}
+
+type jsonRpcResponse struct {
+	Result string `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// ethCall executes a read-only call on the first JSON-RPC url
+// that answers and returns the raw ABI encoded result.
+//
+// API doc: https://ethereum.org/en/developers/docs/apis/json-rpc/#eth_call
+func (p *UniswapProvider) ethCall(contract, data string) ([]byte, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "eth_call",
+		"params": []interface{}{
+			map[string]string{"to": contract, "data": data},
+			"latest",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []string
+	for _, url := range p.config.Urls {
+		res, err := p.post(url, body)
+		if err == nil {
+			return res, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", url, err))
+	}
+	return nil, fmt.Errorf("eth_call failed on every url: %s", strings.Join(errs, "; "))
+}
+
+func (p *UniswapProvider) post(url string, body []byte) ([]byte, error) {
+	resp, err := p.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	resBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var res jsonRpcResponse
+	if err := json.Unmarshal(resBody, &res); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s", string(resBody))
+	}
+	if res.Error != nil {
+		return nil, fmt.Errorf("rpc error %d: %s", res.Error.Code, res.Error.Message)
+	}
+	return hex.DecodeString(strings.TrimPrefix(res.Result, "0x"))
+}
+
+// word returns the nth 32 bytes word of an ABI encoded result.
+func word(data []byte, n int) (*big.Int, error) {
+	if len(data) < (n+1)*32 {
+		return nil, fmt.Errorf("result too short: 0x%x", data)
+	}
+	return new(big.Int).SetBytes(data[n*32 : (n+1)*32]), nil
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,145 @@
+package uniswap_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/provider/internal/uniswap"
+)
+
+const (
+	pool = "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+	usdc = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	weth = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+)
+
+// word encodes a value as a 32 bytes ABI word.
+func word(value *big.Int) string {
+	return fmt.Sprintf("%064x", value)
+}
+
+func address(addr string) string {
+	return fmt.Sprintf("%064s", strings.TrimPrefix(addr, "0x"))
+}
+
+// startNode starts a JSON-RPC node answering eth_call for a
+// USDC/WETH pool (token0 is USDC) where 1 ETH is worth 2000 USDC.
+func startNode(t *testing.T) string {
+	// sqrtPriceX96 = sqrt(1 / 2000 * 10^(18-6)) * 2^96
+	sqrtPrice := new(big.Float).SetPrec(256).SetFloat64(math.Sqrt(1e12 / 2000))
+	sqrtPrice.Mul(sqrtPrice, new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), 96)))
+	sqrtPriceX96, _ := sqrtPrice.Int(nil)
+
+	results := map[string]string{
+		pool + "0x3850c7bd": word(sqrtPriceX96) + word(big.NewInt(201000)),
+		pool + "0x0dfe1681": address(usdc),
+		pool + "0xd21220a7": address(weth),
+		usdc + "0x313ce567": word(big.NewInt(6)),
+		weth + "0x313ce567": word(big.NewInt(18)),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Id     int `json:"id"`
+			Params []json.RawMessage
+		}
+		var call struct {
+			To   string `json:"to"`
+			Data string `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Params) == 0 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		_ = json.Unmarshal(req.Params[0], &call)
+
+		result, ok := results[strings.ToLower(call.To)+call.Data]
+		if !ok {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      req.Id,
+				"error":   map[string]interface{}{"code": -32000, "message": "execution reverted"},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.Id,
+			"result":  "0x" + result,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestUniswapProvider(t *testing.T) {
+	// GIVEN a dead node followed by a working one
+	dead := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer dead.Close()
+	cfg := &config.ProviderConfig{
+		Interval: 1,
+		Timeout:  1,
+		Urls:     []string{dead.URL, startNode(t)},
+		Pools: []config.PoolConfig{
+			{Pair: "ETH/USDC", Id: pool, BaseDenom: strings.ToUpper(weth)},
+			{Pair: "USDC/ETH", Id: pool},
+			{Pair: "DAI/USDC", Id: pool, BaseDenom: "0x6b175474e89094c44da98b954eedeac495271d0f"},
+			// the quote token alone orients the pool
+			{Pair: "WETH/USDC", Id: pool, QuoteDenom: usdc},
+			{Pair: "USDC/WETH", Id: pool, QuoteDenom: weth},
+			{Pair: "ETH/DAI", Id: pool, BaseDenom: weth, QuoteDenom: "0x6b175474e89094c44da98b954eedeac495271d0f"},
+			{Pair: "ETH/ETH", Id: pool, BaseDenom: weth, QuoteDenom: weth},
+		},
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	// WHEN
+	provider, err := uniswap.NewUniswapProvider(cfg, stopCh)
+	require.NoError(t, err)
+
+	// THEN
+	require.Eventually(t, func() bool { return len(provider.GetPrices()) == 4 }, 5*time.Second, 10*time.Millisecond)
+	prices := provider.GetPrices()
+	require.InDelta(t, 2000, prices["ETH/USDC"].Price, 1e-6)
+	require.InDelta(t, 1.0/2000, prices["USDC/ETH"].Price, 1e-12)
+	require.InDelta(t, 2000, prices["WETH/USDC"].Price, 1e-6)
+	require.InDelta(t, 1.0/2000, prices["USDC/WETH"].Price, 1e-12)
+	require.NotContains(t, prices, "DAI/USDC")
+	require.NotContains(t, prices, "ETH/DAI")
+	require.NotContains(t, prices, "ETH/ETH")
+}
+
+func TestUniswapProviderDefaultInterval(t *testing.T) {
+	// GIVEN a config without interval
+	cfg := &config.ProviderConfig{
+		Urls:  []string{startNode(t)},
+		Pools: []config.PoolConfig{{Pair: "ETH/USDC", Id: pool, BaseDenom: weth}},
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	// WHEN
+	provider, err := uniswap.NewUniswapProvider(cfg, stopCh)
+
+	// THEN it polls every DefaultInterval rather than panicking
+	require.NoError(t, err)
+	require.Equal(t, config.DefaultInterval*time.Second, cfg.PollInterval())
+	require.Eventually(t, func() bool { return len(provider.GetPrices()) == 1 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestUniswapProviderWithoutUrls(t *testing.T) {
+	_, err := uniswap.NewUniswapProvider(&config.ProviderConfig{Interval: 1}, make(chan struct{}))
+	require.Error(t, err)
+}
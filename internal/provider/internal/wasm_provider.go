@@ -0,0 +1,272 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/metrics"
+	"github.com/terra-money/oracle-feeder-go/internal/reporting"
+	internal_types "github.com/terra-money/oracle-feeder-go/internal/types"
+	"github.com/terra-money/oracle-feeder-go/pkg/types"
+	"google.golang.org/grpc"
+)
+
+// ErrInsufficientLiquidity is returned by the price funcs of the pools too shallow
+// to be priced, whose last price is withdrawn rather than served as stale.
+var ErrInsufficientLiquidity = errors.New("insufficient liquidity")
+
+// defaultMaxStaleAge is the age above which the last price of a failing pool is withdrawn.
+const defaultMaxStaleAge = 10 * time.Minute
+
+// WasmPriceFunc returns the price of the base coin of the pool in quote coin and its price impact.
+type WasmPriceFunc func(querier *WasmQuerier, pool config.PoolConfig) (float64, float64, error)
+
+// WasmProvider prices the configured pools by querying their contracts on the nodes of a chain.
+type WasmProvider struct {
+	BaseGrpc
+	exchange      string
+	defaultUrl    string
+	fetchPrice    WasmPriceFunc
+	priceBySymbol map[string]internal_types.PriceBySymbol
+	config        *config.ProviderConfig
+	mu            *sync.Mutex
+}
+
+// WasmQuerier sends smart queries to the nodes of a chain.
+type WasmQuerier struct {
+	conns   []*grpc.ClientConn
+	timeout time.Duration
+}
+
+func NewWasmProvider(exchange, defaultUrl string, config *config.ProviderConfig, fetchPrice WasmPriceFunc, stopCh <-chan struct{}) (*WasmProvider, error) {
+	mu := sync.Mutex{}
+	provider := &WasmProvider{
+		BaseGrpc:      *NewBaseGrpc(),
+		exchange:      exchange,
+		defaultUrl:    defaultUrl,
+		fetchPrice:    fetchPrice,
+		priceBySymbol: make(map[string]internal_types.PriceBySymbol),
+		config:        config,
+		mu:            &mu,
+	}
+
+	go func() {
+		defer reporting.Recover()
+		ticker := time.NewTicker(config.PollInterval())
+		provider.fetchAndParse()
+		for {
+			select {
+			case <-stopCh:
+				ticker.Stop()
+				return
+			case <-ticker.C:
+				provider.fetchAndParse()
+			}
+		}
+	}()
+
+	return provider, nil
+}
+
+func (p *WasmProvider) GetPrices() map[string]types.PriceByPair {
+	result := make(map[string]types.PriceByPair)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, price := range p.priceBySymbol {
+		pair := fmt.Sprintf("%s/%s", price.Base, price.Quote)
+		result[pair] = types.PriceByPair{
+			Base:        price.Base,
+			Quote:       price.Quote,
+			Price:       price.Price,
+			Timestamp:   price.Timestamp,
+			PriceImpact: price.PriceImpact,
+			Stale:       price.Stale,
+		}
+	}
+	return result
+}
+
+func (p *WasmProvider) fetchAndParse() {
+	urls := p.config.Urls
+	if len(urls) == 0 {
+		urls = []string{p.defaultUrl}
+	}
+	timeout := p.config.Timeout
+	if timeout == 0 {
+		timeout = 10
+	}
+	querier := &WasmQuerier{timeout: time.Duration(timeout) * time.Second}
+	for _, url := range urls {
+		conn, err := p.BaseGrpc.Connection(context.Background(), url)
+		if err != nil {
+			log.Printf("%s %s: %v", p.exchange, url, err)
+			reporting.CaptureError(err, map[string]string{"exchange": p.exchange})
+			continue
+		}
+		defer conn.Close()
+		querier.conns = append(querier.conns, conn)
+	}
+
+	// pools are queried concurrently and a failing pool keeps its last price, as stale, until it expires
+	var wg sync.WaitGroup
+	for _, pool := range p.config.Pools {
+		wg.Add(1)
+		go func(pool config.PoolConfig) {
+			defer reporting.Recover()
+			defer wg.Done()
+			pair := strings.Split(pool.Pair, "/")
+			if len(pair) != 2 {
+				log.Printf("%s: invalid pair %s", p.exchange, pool.Pair)
+				return
+			}
+			price, priceImpact, err := p.fetchPrice(querier, pool)
+			if err != nil {
+				log.Printf("%s pool %s: %v", p.exchange, pool.Id, err)
+				metrics.ProviderFailed(p.exchange)
+				if errors.Is(err, ErrInsufficientLiquidity) {
+					p.mu.Lock()
+					delete(p.priceBySymbol, pool.Pair)
+					p.mu.Unlock()
+					return
+				}
+				p.expire(pool.Pair)
+				return
+			}
+			if p.config.MaxPriceImpact > 0 && priceImpact > p.config.MaxPriceImpact {
+				log.Printf("%s pool %s rejected: price impact %f exceeds %f", p.exchange, pool.Id, priceImpact, p.config.MaxPriceImpact)
+				p.mu.Lock()
+				delete(p.priceBySymbol, pool.Pair)
+				p.mu.Unlock()
+				return
+			}
+
+			p.mu.Lock()
+			p.priceBySymbol[pool.Pair] = internal_types.PriceBySymbol{
+				Exchange:    p.exchange,
+				Symbol:      pool.Id,
+				Base:        pair[0],
+				Quote:       pair[1],
+				Price:       price,
+				Timestamp:   uint64(time.Now().UnixMilli()),
+				PriceImpact: priceImpact,
+			}
+			p.mu.Unlock()
+			metrics.ProviderUpdated(p.exchange)
+		}(pool)
+	}
+	wg.Wait()
+}
+
+// expire marks the last price of a pair which failed to be fetched as stale,
+// and withdraws it once it is older than the max stale age.
+func (p *WasmProvider) expire(pair string) {
+	maxStaleAge := defaultMaxStaleAge
+	if p.config.MaxStaleAge > 0 {
+		maxStaleAge = time.Duration(p.config.MaxStaleAge) * time.Second
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	price, ok := p.priceBySymbol[pair]
+	if !ok {
+		return
+	}
+	if time.Since(time.UnixMilli(int64(price.Timestamp))) > maxStaleAge {
+		delete(p.priceBySymbol, pair)
+		return
+	}
+	price.Stale = true
+	p.priceBySymbol[pair] = price
+}
+
+// QuerySmartContract sends the query to the first node that answers.
+func (q *WasmQuerier) QuerySmartContract(contract string, query []byte, res interface{}) error {
+	var errs []string
+	for _, conn := range q.conns {
+		ctx, cancel := context.WithTimeout(context.Background(), q.timeout)
+		data, err := wasmtypes.NewQueryClient(conn).SmartContractState(ctx, &wasmtypes.QuerySmartContractStateRequest{
+			Address:   contract,
+			QueryData: query,
+		})
+		cancel()
+		if err == nil {
+			return json.Unmarshal(data.Data, res)
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", conn.Target(), err))
+	}
+	return fmt.Errorf("query failed on every node: %s", strings.Join(errs, "; "))
+}
+
+// SimulationQuery is the query of the swap of tradeSize base coins of the pool,
+// cw20 tokens being the denoms starting with cw20Prefix.
+func SimulationQuery(pool config.PoolConfig, tradeSize float64, cw20Prefix string) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"simulation": map[string]interface{}{
+			"offer_asset": map[string]interface{}{
+				"info":   AssetInfo(pool.BaseDenom, cw20Prefix),
+				"amount": strconv.FormatFloat(tradeSize*math.Pow10(pool.BaseExponent), 'f', 0, 64),
+			},
+		},
+	})
+}
+
+type poolResponse struct {
+	Assets []struct {
+		Info   internal_types.OfferAssetInfo `json:"info"`
+		Amount string                        `json:"amount"`
+	} `json:"assets"`
+}
+
+// PoolPrice returns the price of the base coin in quote coin from
+// the reserves of an Astroport or White Whale pair.
+func PoolPrice(querier *WasmQuerier, pool config.PoolConfig) (float64, error) {
+	var res poolResponse
+	if err := querier.QuerySmartContract(pool.Id, []byte(`{"pool":{}}`), &res); err != nil {
+		return 0, err
+	}
+	var baseAmount, quoteAmount float64
+	for _, asset := range res.Assets {
+		amount, err := strconv.ParseFloat(asset.Amount, 64)
+		if err != nil {
+			return 0, err
+		}
+		switch DenomOf(asset.Info) {
+		case pool.BaseDenom:
+			baseAmount = amount
+		case pool.QuoteDenom:
+			quoteAmount = amount
+		}
+	}
+	if baseAmount == 0 || quoteAmount == 0 {
+		return 0, fmt.Errorf("%w for %s in the pair", ErrInsufficientLiquidity, pool.Pair)
+	}
+	return quoteAmount / baseAmount * math.Pow10(pool.BaseExponent-pool.QuoteExponent), nil
+}
+
+// AssetInfo returns the asset info of a denom, cw20 tokens
+// are identified by their contract address.
+func AssetInfo(denom, cw20Prefix string) internal_types.OfferAssetInfo {
+	if strings.HasPrefix(denom, cw20Prefix) {
+		return internal_types.OfferAssetInfo{Token: &internal_types.Token{ContractAddr: denom}}
+	}
+	return internal_types.OfferAssetInfo{NativeToken: &internal_types.AstroNativeToken{Denom: denom}}
+}
+
+func DenomOf(info internal_types.OfferAssetInfo) string {
+	if info.Token != nil {
+		return info.Token.ContractAddr
+	}
+	if info.NativeToken != nil {
+		return info.NativeToken.Denom
+	}
+	return ""
+}
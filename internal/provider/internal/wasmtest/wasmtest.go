@@ -0,0 +1,54 @@
+// Package wasmtest serves the smart queries of the wasm providers in their tests.
+package wasmtest
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/pkg/types"
+	"google.golang.org/grpc"
+)
+
+// QueryFunc answers the smart query of a contract, keyed by query name.
+type QueryFunc func(contract string, query map[string]json.RawMessage) (string, error)
+
+type server struct {
+	wasmtypes.UnimplementedQueryServer
+	answer QueryFunc
+}
+
+func (s *server) SmartContractState(ctx context.Context, req *wasmtypes.QuerySmartContractStateRequest) (*wasmtypes.QuerySmartContractStateResponse, error) {
+	var query map[string]json.RawMessage
+	if err := json.Unmarshal(req.QueryData, &query); err != nil {
+		return nil, err
+	}
+	data, err := s.answer(req.Address, query)
+	if err != nil {
+		return nil, err
+	}
+	return &wasmtypes.QuerySmartContractStateResponse{Data: []byte(data)}, nil
+}
+
+// StartNode serves the smart queries until the end of the test and returns the address of the node.
+func StartNode(t *testing.T, answer QueryFunc) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	wasmtypes.RegisterQueryServer(grpcServer, &server{answer: answer})
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+	return listener.Addr().String()
+}
+
+// WaitForPrices waits for the provider to price count pairs and returns its prices.
+func WaitForPrices(t *testing.T, provider interface {
+	GetPrices() map[string]types.PriceByPair
+}, count int) map[string]types.PriceByPair {
+	require.Eventually(t, func() bool { return len(provider.GetPrices()) == count }, 5*time.Second, 10*time.Millisecond)
+	return provider.GetPrices()
+}
@@ -3,47 +3,143 @@ package internal
 import (
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/reporting"
 	internal_types "github.com/terra-money/oracle-feeder-go/internal/types"
 	"github.com/terra-money/oracle-feeder-go/internal/websocket"
 	"github.com/terra-money/oracle-feeder-go/pkg/types"
 )
 
+// default VWAP window of the trades mode
+const defaultTradeWindow = time.Minute
+
 type WebsocketProvider struct {
 	priceBySymbol map[string]internal_types.PriceBySymbol
 	mu            *sync.Mutex
 }
 
-func NewWebsocketProvider(exchange string, symbols []string, stopCh <-chan struct{}) (*WebsocketProvider, error) {
-	candlestickCh, err := websocket.SubscribeCandlestick(exchange, symbols, stopCh)
-	if err != nil {
-		return nil, err
-	}
-
+func NewWebsocketProvider(exchange string, config *config.ProviderConfig, stopCh <-chan struct{}) (*WebsocketProvider, error) {
 	mu := sync.Mutex{}
 	provider := &WebsocketProvider{
 		priceBySymbol: make(map[string]internal_types.PriceBySymbol),
 		mu:            &mu,
 	}
 
-	go func() {
-		for msg := range candlestickCh {
-			price := internal_types.PriceBySymbol{
-				Exchange:  msg.Exchange,
-				Symbol:    msg.Symbol,
-				Base:      msg.Base,
-				Quote:     msg.Quote,
-				Price:     msg.Vwap,
-				Timestamp: msg.Timestamp,
+	switch config.Mode {
+	case "", "kline":
+		candlestickCh, err := websocket.SubscribeCandlestick(exchange, config.Symbols, stopCh)
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			defer reporting.Recover()
+			for msg := range candlestickCh {
+				provider.setPrice(internal_types.PriceBySymbol{
+					Exchange:  msg.Exchange,
+					Symbol:    msg.Symbol,
+					Base:      msg.Base,
+					Quote:     msg.Quote,
+					Price:     msg.Vwap,
+					Timestamp: msg.Timestamp,
+				})
+			}
+		}()
+	case "book_ticker":
+		bookTickerCh, err := websocket.SubscribeBookTicker(exchange, config.Symbols, stopCh)
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			defer reporting.Recover()
+			for msg := range bookTickerCh {
+				// one side of the book is empty, there is no mid price
+				if msg.Bid <= 0 || msg.Ask <= 0 {
+					continue
+				}
+				provider.setPrice(internal_types.PriceBySymbol{
+					Exchange:  msg.Exchange,
+					Symbol:    msg.Symbol,
+					Base:      msg.Base,
+					Quote:     msg.Quote,
+					Price:     msg.Mid,
+					Timestamp: msg.Timestamp,
+					Spread:    msg.Spread,
+				})
 			}
-			mu.Lock()
-			provider.priceBySymbol[msg.Symbol] = price
-			mu.Unlock()
+		}()
+	case "trades":
+		window := defaultTradeWindow
+		if config.Window > 0 {
+			window = time.Duration(config.Window) * time.Second
 		}
-	}()
+		tradeCh, err := websocket.SubscribeTrades(exchange, config.Symbols, stopCh)
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			defer reporting.Recover()
+			tradesBySymbol := make(map[string][]internal_types.Trade)
+			for msg := range tradeCh {
+				trades := append(tradesBySymbol[msg.Symbol], msg.Trades...)
+				trades = truncateTrades(trades, window, time.Now())
+				tradesBySymbol[msg.Symbol] = trades
+				vwap, timestamp, ok := tradesVwap(trades)
+				if !ok {
+					continue
+				}
+				provider.setPrice(internal_types.PriceBySymbol{
+					Exchange:  msg.Exchange,
+					Symbol:    msg.Symbol,
+					Base:      msg.Base,
+					Quote:     msg.Quote,
+					Price:     vwap,
+					Timestamp: timestamp,
+				})
+			}
+		}()
+	default:
+		return nil, fmt.Errorf("unknown mode %s for %s", config.Mode, exchange)
+	}
 	return provider, nil
 }
 
+func (p *WebsocketProvider) setPrice(price internal_types.PriceBySymbol) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.priceBySymbol[price.Symbol] = price
+}
+
+// truncateTrades drops the trades older than the window, which ends now.
+func truncateTrades(trades []internal_types.Trade, window time.Duration, now time.Time) []internal_types.Trade {
+	start := now.Add(-window).UnixMilli()
+	result := trades[:0]
+	for _, trade := range trades {
+		if int64(trade.Timestamp) > start {
+			result = append(result, trade)
+		}
+	}
+	return result
+}
+
+// tradesVwap returns the volume weighted average price of the trades and the time of the latest one.
+func tradesVwap(trades []internal_types.Trade) (float64, uint64, bool) {
+	var baseVolume, quoteVolume float64
+	var timestamp uint64
+	for _, trade := range trades {
+		baseVolume += trade.Volume
+		quoteVolume += trade.Price * trade.Volume
+		if trade.Timestamp > timestamp {
+			timestamp = trade.Timestamp
+		}
+	}
+	if baseVolume <= 0 {
+		return 0, 0, false
+	}
+	return quoteVolume / baseVolume, timestamp, true
+}
+
 func (p *WebsocketProvider) GetPrices() map[string]types.PriceByPair {
 	result := make(map[string]types.PriceByPair)
 	p.mu.Lock()
@@ -55,6 +151,7 @@ func (p *WebsocketProvider) GetPrices() map[string]types.PriceByPair {
 			Quote:     price.Quote,
 			Price:     price.Price,
 			Timestamp: price.Timestamp,
+			Spread:    price.Spread,
 		}
 	}
 	return result
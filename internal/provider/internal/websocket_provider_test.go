@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	internal_types "github.com/terra-money/oracle-feeder-go/internal/types"
+)
+
+func TestTradesVwap(t *testing.T) {
+	now := time.UnixMilli(1700000060000)
+	ms := func(ago time.Duration) uint64 {
+		return uint64(now.Add(-ago).UnixMilli())
+	}
+	tests := []struct {
+		name      string
+		trades    []internal_types.Trade
+		ok        bool
+		vwap      float64
+		timestamp uint64
+	}{
+		{
+			name: "empty window",
+		},
+		{
+			name:      "single trade",
+			trades:    []internal_types.Trade{{Timestamp: ms(10 * time.Second), Price: 30000, Volume: 0.5}},
+			ok:        true,
+			vwap:      30000,
+			timestamp: ms(10 * time.Second),
+		},
+		{
+			name: "expired trades",
+			trades: []internal_types.Trade{
+				{Timestamp: ms(2 * time.Minute), Price: 10000, Volume: 10},
+				{Timestamp: ms(time.Minute), Price: 20000, Volume: 10},
+				{Timestamp: ms(30 * time.Second), Price: 30000, Volume: 1},
+				{Timestamp: ms(5 * time.Second), Price: 30300, Volume: 2},
+			},
+			ok:        true,
+			vwap:      30200,
+			timestamp: ms(5 * time.Second),
+		},
+		{
+			name: "every trade expired",
+			trades: []internal_types.Trade{
+				{Timestamp: ms(3 * time.Minute), Price: 30000, Volume: 1},
+				{Timestamp: ms(2 * time.Minute), Price: 30100, Volume: 1},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// GIVEN trades of the last minutes
+			// WHEN their one minute window ending now is averaged
+			trades := truncateTrades(test.trades, time.Minute, now)
+			vwap, timestamp, ok := tradesVwap(trades)
+
+			// THEN only the trades of the window are weighted
+			require.Equal(t, test.ok, ok)
+			require.InDelta(t, test.vwap, vwap, 1e-9)
+			require.Equal(t, test.timestamp, timestamp)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package whitewhale
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/provider/internal"
+)
+
+// WhiteWhaleProvider prices pairs by querying the White Whale pair contracts on Migaloo.
+type WhiteWhaleProvider struct {
+	*internal.WasmProvider
+	config *config.ProviderConfig
+}
+
+// see https://github.com/White-Whale-Defi-Platform/white-whale-core/blob/main/packages/white-whale/src/pool_network/pair.rs
+type simulationResponse struct {
+	ReturnAmount      string `json:"return_amount"`
+	SpreadAmount      string `json:"spread_amount"`
+	SwapFeeAmount     string `json:"swap_fee_amount"`
+	ProtocolFeeAmount string `json:"protocol_fee_amount"`
+	BurnFeeAmount     string `json:"burn_fee_amount"`
+}
+
+func NewWhiteWhaleProvider(providerConfig *config.ProviderConfig, stopCh <-chan struct{}) (*WhiteWhaleProvider, error) {
+	provider := &WhiteWhaleProvider{config: providerConfig}
+	wasmProvider, err := internal.NewWasmProvider("whitewhale", config.MIGALOO_GRPC, providerConfig, provider.fetchPrice, stopCh)
+	if err != nil {
+		return nil, err
+	}
+	provider.WasmProvider = wasmProvider
+	return provider, nil
+}
+
+// fetchPrice returns the price of the base coin in quote coin and its price impact,
+// either by simulating a swap of the trade size or from the reserves of the pair.
+func (p *WhiteWhaleProvider) fetchPrice(querier *internal.WasmQuerier, pool config.PoolConfig) (float64, float64, error) {
+	switch pool.Query {
+	case "", "simulation":
+		tradeSize := 1.0
+		if size, ok := p.config.TradeSizes[pool.Pair]; ok && size > 0 {
+			tradeSize = size
+		}
+		query, err := internal.SimulationQuery(pool, tradeSize, "migaloo1")
+		if err != nil {
+			return 0, 0, err
+		}
+		var res simulationResponse
+		if err := querier.QuerySmartContract(pool.Id, query, &res); err != nil {
+			return 0, 0, err
+		}
+		// the fees are not part of the price and the spread is what the swap loses to the price impact
+		var amount, spreadAmount float64
+		for _, value := range []string{res.ReturnAmount, res.SwapFeeAmount, res.ProtocolFeeAmount, res.BurnFeeAmount} {
+			if value == "" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return 0, 0, err
+			}
+			amount += parsed
+		}
+		if spreadAmount, err = strconv.ParseFloat(res.SpreadAmount, 64); err != nil {
+			return 0, 0, err
+		}
+		if amount <= 0 {
+			return 0, 0, fmt.Errorf("%w for %s in the pair", internal.ErrInsufficientLiquidity, pool.Pair)
+		}
+		return amount / math.Pow10(pool.QuoteExponent) / tradeSize, spreadAmount / (amount + spreadAmount), nil
+	case "pool":
+		price, err := internal.PoolPrice(querier, pool)
+		return price, 0, err
+	default:
+		return 0, 0, fmt.Errorf("unknown query %s", pool.Query)
+	}
+}
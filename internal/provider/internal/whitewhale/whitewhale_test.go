@@ -0,0 +1,90 @@
+package whitewhale_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/provider/internal/wasmtest"
+	"github.com/terra-money/oracle-feeder-go/internal/provider/internal/whitewhale"
+)
+
+const (
+	usdc     = "ibc/USDC"
+	ampWhale = "factory/migaloo1436kxs0w2es6xlqpp9rd35e3d0cjnw4sv8j3a7483sgks29jqwgshqdky4/ampWHALE"
+	bWhale   = "factory/migaloo1mf6ptkssddfmxvhdx0ech0k03ktp6kf9yk59renau2gvht3nq2gqdhts4u/boneWhale"
+)
+
+// answer the simulation of a WHALE/USDC pair where 1 WHALE is worth 0.02 USDC,
+// the reserves of a ampWHALE/WHALE pair, the simulation of a bWHALE/WHALE pair
+// offering bWHALE where 1 bWHALE is worth 1.05 WHALE and the simulation of 1.1
+// quote coins for 1 base coin in any other pair.
+func answer(contract string, query map[string]json.RawMessage) (string, error) {
+	switch {
+	case contract == "migaloo1bwhalewhale" && query["simulation"] != nil:
+		if !strings.Contains(string(query["simulation"]), bWhale) {
+			return "", fmt.Errorf("unexpected offer %s", string(query["simulation"]))
+		}
+		return `{"return_amount":"1050000","spread_amount":"0","swap_fee_amount":"0","protocol_fee_amount":"0","burn_fee_amount":"0"}`, nil
+	case contract == "migaloo1whaleusdc" && query["simulation"] != nil:
+		return `{"return_amount":"19000","spread_amount":"200","swap_fee_amount":"600","protocol_fee_amount":"300","burn_fee_amount":"100"}`, nil
+	case query["simulation"] != nil:
+		return `{"return_amount":"1100000000","spread_amount":"0","swap_fee_amount":"0","protocol_fee_amount":"0","burn_fee_amount":"0"}`, nil
+	case contract == "migaloo1ampwhalewhale" && query["pool"] != nil:
+		return `{"assets":[
+			{"info":{"native_token":{"denom":"` + ampWhale + `"}},"amount":"1000000000"},
+			{"info":{"native_token":{"denom":"uwhale"}},"amount":"1300000000"}
+		],"total_share":"1000000"}`, nil
+	default:
+		return "", fmt.Errorf("contract %s not found", contract)
+	}
+}
+
+func TestWhiteWhaleProvider(t *testing.T) {
+	// GIVEN
+	cfg := &config.ProviderConfig{
+		Interval: 1,
+		Timeout:  1,
+		Urls:     []string{wasmtest.StartNode(t, answer)},
+		Pools: []config.PoolConfig{
+			{Pair: "WHALE/USDC", Id: "migaloo1whaleusdc", BaseDenom: "uwhale", BaseExponent: 6, QuoteDenom: usdc, QuoteExponent: 6},
+			{Pair: "AMPWHALE/WHALE", Id: "migaloo1ampwhalewhale", BaseDenom: ampWhale, BaseExponent: 6, QuoteDenom: "uwhale", QuoteExponent: 6, Query: "pool"},
+			{Pair: "BWHALE/WHALE", Id: "migaloo1bwhalewhale", BaseDenom: bWhale, BaseExponent: 6, QuoteDenom: "uwhale", QuoteExponent: 6},
+		},
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	// WHEN
+	provider, err := whitewhale.NewWhiteWhaleProvider(cfg, stopCh)
+	require.NoError(t, err)
+
+	// THEN
+	prices := wasmtest.WaitForPrices(t, provider, 3)
+	require.InDelta(t, 0.02, prices["WHALE/USDC"].Price, 1e-9)
+	require.InDelta(t, 0.01, prices["WHALE/USDC"].PriceImpact, 1e-3)
+	require.InDelta(t, 1.3, prices["AMPWHALE/WHALE"].Price, 1e-9)
+	require.InDelta(t, 1.05, prices["BWHALE/WHALE"].Price, 1e-9)
+}
+
+func TestWhiteWhaleDefaultConfig(t *testing.T) {
+	// GIVEN the default config served by a node
+	cfg := config.DefaultPriceServerConfig.Providers["whitewhale"]
+	cfg.Urls = []string{wasmtest.StartNode(t, answer)}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	// WHEN
+	provider, err := whitewhale.NewWhiteWhaleProvider(&cfg, stopCh)
+	require.NoError(t, err)
+
+	// THEN the provider runs and prices WHALE, ampWHALE and bWHALE from Migaloo
+	require.Contains(t, config.DefaultPriceServerConfig.ProviderPriority, "whitewhale")
+	prices := wasmtest.WaitForPrices(t, provider, 3)
+	require.Contains(t, prices, "WHALE/USDC")
+	require.InDelta(t, 1.1, prices["AMPWHALE/WHALE"].Price, 1e-9)
+	require.InDelta(t, 1.1, prices["BWHALE/WHALE"].Price, 1e-9)
+}
@@ -43,7 +43,17 @@ func NewLSDProvider() *LSDProvider {
 	}
 }
 
+// QueryLSTRebaseFactor returns the rebase factor of the LST, its errors
+// are upstream errors of the LST symbol.
 func (p *LSDProvider) QueryLSTRebaseFactor(ctx context.Context, symbol string) (*sdk.Dec, error) {
+	rebaseFactor, err := p.queryLSTRebaseFactor(ctx, symbol)
+	if err != nil {
+		return nil, types.NewUpstreamError(symbol, err)
+	}
+	return rebaseFactor, nil
+}
+
+func (p *LSDProvider) queryLSTRebaseFactor(ctx context.Context, symbol string) (*sdk.Dec, error) {
 	switch symbol {
 	case "AMPLUNA":
 		return p.queryAmpRebaseFactor(ctx, config.PHOENIX_GRPC, p.ampSTHubLuna)
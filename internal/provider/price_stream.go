@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/terra-money/oracle-feeder-go/internal/reporting"
+	"github.com/terra-money/oracle-feeder-go/pkg/types"
+)
+
+// streamInterval is how often the prices are recomputed for the subscribers.
+const streamInterval = time.Second
+
+// Subscribe returns the prices recomputed every streamInterval, computed once
+// for all the subscribers. A subscriber too slow to read misses the updates
+// sent in the meantime. The channel is closed when ctx is done.
+func (m *ProviderManager) Subscribe(ctx context.Context) <-chan *types.PricesResponse {
+	ch := make(chan *types.PricesResponse, 1)
+	m.mu.Lock()
+	m.subscribers[ch] = struct{}{}
+	m.mu.Unlock()
+
+	go func() {
+		defer reporting.Recover()
+		<-ctx.Done()
+		m.mu.Lock()
+		delete(m.subscribers, ch)
+		close(ch)
+		m.mu.Unlock()
+	}()
+	return ch
+}
+
+// StreamPrices returns the prices of the denoms, or of every denom when empty,
+// whenever they move by at least minChange, a fraction, from the last price
+// sent. The first price of each denom is always sent.
+func (m *ProviderManager) StreamPrices(ctx context.Context, denoms []string, minChange float64) <-chan types.PriceOfCoin {
+	filter := make(map[string]bool)
+	for _, denom := range denoms {
+		filter[strings.ToUpper(denom)] = true
+	}
+
+	out := make(chan types.PriceOfCoin)
+	go func() {
+		defer reporting.Recover()
+		defer close(out)
+		lastSent := make(map[string]float64)
+		for res := range m.Subscribe(ctx) {
+			for _, price := range res.Prices {
+				if len(filter) > 0 && !filter[strings.ToUpper(price.Denom)] {
+					continue
+				}
+				if last, ok := lastSent[price.Denom]; ok && math.Abs(price.Price-last) < minChange*last {
+					continue
+				}
+				select {
+				case out <- price:
+					lastSent[price.Denom] = price.Price
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// publish recomputes the prices when someone subscribed to them until stopCh is closed.
+func (m *ProviderManager) publish(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(streamInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			m.mu.Lock()
+			subscribed := len(m.subscribers) > 0
+			m.mu.Unlock()
+			if !subscribed {
+				continue
+			}
+
+			res := m.GetPrices(context.Background())
+			m.mu.Lock()
+			for ch := range m.subscribers {
+				select {
+				case ch <- res:
+				default:
+				}
+			}
+			m.mu.Unlock()
+		}
+	}
+}
@@ -6,7 +6,11 @@ import (
 
 	"github.com/terra-money/oracle-feeder-go/config"
 	"github.com/terra-money/oracle-feeder-go/internal/provider/internal"
+	"github.com/terra-money/oracle-feeder-go/internal/provider/internal/astroport"
+	"github.com/terra-money/oracle-feeder-go/internal/provider/internal/kujira"
 	"github.com/terra-money/oracle-feeder-go/internal/provider/internal/osmosis"
+	"github.com/terra-money/oracle-feeder-go/internal/provider/internal/uniswap"
+	"github.com/terra-money/oracle-feeder-go/internal/provider/internal/whitewhale"
 	"github.com/terra-money/oracle-feeder-go/pkg/types"
 )
 
@@ -19,12 +23,24 @@ type Provider interface {
 
 func NewProvider(exchange string, config *config.ProviderConfig, stopCh <-chan struct{}) (Provider, error) {
 	switch strings.ToLower(exchange) {
-	case "binance", "bitfinex", "bybit", "coinbase", "huobi", "kraken", "kucoin", "okx":
-		return internal.NewWebsocketProvider(exchange, config.Symbols, stopCh)
-	case "astroport", "bitstamp", "bittrex", "coingecko", "exchangerate", "fer", "frankfurter":
-		return internal.NewRESTfulProvider(exchange, config.Symbols, config.Interval, config.Timeout, stopCh)
+	case "binance", "bitfinex", "bybit", "coinbase", "cryptocom", "gateio", "huobi", "kraken", "kucoin", "mexc", "okx":
+		return internal.NewWebsocketProvider(exchange, config, stopCh)
+	case "astroport":
+		// query the pair contracts directly when they are configured, the router API otherwise
+		if len(config.Pools) > 0 {
+			return astroport.NewAstroportProvider(config, stopCh)
+		}
+		return internal.NewRESTfulProvider(exchange, config, stopCh)
+	case "bitstamp", "bittrex", "coingecko", "exchangerate", "fer", "frankfurter", "pyth":
+		return internal.NewRESTfulProvider(exchange, config, stopCh)
+	case "kujira":
+		return kujira.NewKujiraProvider(config, stopCh)
 	case "osmosis":
 		return osmosis.NewOsmosisProvider(config, stopCh)
+	case "uniswap":
+		return uniswap.NewUniswapProvider(config, stopCh)
+	case "whitewhale":
+		return whitewhale.NewWhiteWhaleProvider(config, stopCh)
 	default:
 		return nil, fmt.Errorf("unknown exchange %s", exchange)
 	}
@@ -3,21 +3,34 @@ package provider
 import (
 	"context"
 	"fmt"
+	"log"
+	"math"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/terra-money/oracle-feeder-go/config"
+	"github.com/terra-money/oracle-feeder-go/internal/metrics"
+	"github.com/terra-money/oracle-feeder-go/internal/reporting"
 	"github.com/terra-money/oracle-feeder-go/pkg/types"
 )
 
 type ProviderManager struct {
 	config    *config.Config
 	providers map[string]Provider
+	// independent source the averaged prices are checked against, nil if not configured
+	reference Provider
+	// channels of the Subscribe callers
+	subscribers map[chan *types.PricesResponse]struct{}
+	mu          *sync.Mutex
 }
 
 func NewProviderManager(config *config.Config, stopCh <-chan struct{}) *ProviderManager {
 	providers := make(map[string]Provider)
 	for _, exchange := range config.ProviderPriority {
+		if exchange == config.Reference.Provider {
+			continue
+		}
 		providerConfig := config.Providers[exchange]
 		provider, err := NewProvider(exchange, &providerConfig, stopCh)
 		if err != nil {
@@ -26,10 +39,26 @@ func NewProviderManager(config *config.Config, stopCh <-chan struct{}) *Provider
 			providers[exchange] = provider
 		}
 	}
-	return &ProviderManager{
-		config:    config,
-		providers: providers,
+
+	var reference Provider
+	if exchange := config.Reference.Provider; exchange != "" {
+		providerConfig := config.Providers[exchange]
+		provider, err := NewProvider(exchange, &providerConfig, stopCh)
+		if err != nil {
+			fmt.Printf("Reference %s connection ERROR %s \n", exchange, err)
+		} else {
+			reference = provider
+		}
+	}
+	manager := &ProviderManager{
+		config:      config,
+		providers:   providers,
+		reference:   reference,
+		subscribers: make(map[chan *types.PricesResponse]struct{}),
+		mu:          &sync.Mutex{},
 	}
+	reporting.Go(func() { manager.publish(stopCh) })
+	return manager
 }
 
 func (m *ProviderManager) GetPrices(ctx context.Context) *types.PricesResponse {
@@ -40,15 +69,40 @@ func (m *ProviderManager) GetPrices(ctx context.Context) *types.PricesResponse {
 	}
 
 	priceByCoin := averagePriceByCoin(averagePriceByPair(prices))
+	referenceByCoin := make(map[string]float64)
+	if m.reference != nil {
+		referencePrices := map[string]map[string]types.PriceByPair{m.config.Reference.Provider: m.reference.GetPrices()}
+		referenceByCoin = averagePriceByCoin(averagePriceByPair(referencePrices))
+	}
+	if fallback, ok := m.providers[m.config.Reference.Fallback]; ok {
+		fallbackPrices := map[string]map[string]types.PriceByPair{m.config.Reference.Fallback: fallback.GetPrices()}
+		for coin, price := range averagePriceByCoin(averagePriceByPair(fallbackPrices)) {
+			if _, ok := referenceByCoin[coin]; !ok {
+				referenceByCoin[coin] = price
+			}
+		}
+	}
 
 	var pricesOfCoins []types.PriceOfCoin
 	now := uint64(time.Now().UnixMilli())
 	for coin, price := range priceByCoin {
-		pricesOfCoins = append(pricesOfCoins, types.PriceOfCoin{
+		priceOfCoin := types.PriceOfCoin{
 			Denom:     coin,
 			Price:     price,
 			Timestamp: now,
-		})
+		}
+		if reference, ok := referenceByCoin[coin]; ok && reference > 0 {
+			priceOfCoin.Deviation = math.Abs(price-reference) / reference
+			maxDeviation := m.config.Reference.MaxDeviation
+			if maxDeviation > 0 && priceOfCoin.Deviation > maxDeviation {
+				log.Printf("%s price %f deviates by %f from the reference %f", coin, price, priceOfCoin.Deviation, reference)
+				if m.config.Reference.Halt {
+					continue
+				}
+				priceOfCoin.Divergent = true
+			}
+		}
+		pricesOfCoins = append(pricesOfCoins, priceOfCoin)
 	}
 	resp := &types.PricesResponse{
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
@@ -58,6 +112,42 @@ func (m *ProviderManager) GetPrices(ctx context.Context) *types.PricesResponse {
 	return resp
 }
 
+// GetStatus returns the prices of each provider before averaging,
+// along with the price impact reported by DEX providers, and the
+// prices of the reference provider.
+func (m *ProviderManager) GetStatus(ctx context.Context) *types.StatusResponse {
+	providers := make(map[string]map[string]types.PriceByPair)
+	for exchange, provider := range m.providers {
+		providers[exchange] = provider.GetPrices()
+	}
+	if m.reference != nil {
+		providers[m.config.Reference.Provider] = m.reference.GetPrices()
+	}
+	return &types.StatusResponse{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Providers: providers,
+	}
+}
+
+// GetDenomStats returns the aggregated price of each denom served by
+// GetPrices along with the number of provider prices it comes from.
+func (m *ProviderManager) GetDenomStats(ctx context.Context) map[string]metrics.DenomStats {
+	prices := make(map[string]map[string]types.PriceByPair)
+	for exchange, provider := range m.providers {
+		prices[exchange] = provider.GetPrices()
+	}
+	sources := sourcesByCoin(prices, averagePriceByPair(prices))
+
+	stats := make(map[string]metrics.DenomStats)
+	for _, price := range m.GetPrices(ctx).Prices {
+		stats[price.Denom] = metrics.DenomStats{
+			Price:   price.Price,
+			Sources: sources[price.Denom],
+		}
+	}
+	return stats
+}
+
 func (m *ProviderManager) GetPrice(ctx context.Context, denom string) *types.PriceResponse {
 	r := m.GetPrices(ctx)
 	for _, price := range r.Prices {
@@ -80,6 +170,10 @@ func averagePriceByPair(prices map[string]map[string]types.PriceByPair) map[stri
 	pairCount := make(map[string]float64)
 	for _, priceByPair := range prices {
 		for _, price := range priceByPair {
+			// a last good price served again is no evidence of the current price
+			if price.Stale {
+				continue
+			}
 			pair := fmt.Sprintf("%s/%s", price.Base, price.Quote)
 			pairSum[pair] += price.Price
 			pairCount[pair] += 1.0
@@ -122,3 +216,20 @@ func averagePriceByCoin(prices map[string]types.PriceByPair) map[string]float64
 	}
 	return priceByCoin
 }
+
+// Count the provider prices each coin is averaged from, the same way
+// averagePriceByCoin skips the pairs without a USD price of their quote.
+//
+// Returns map of coin -> number of prices.
+func sourcesByCoin(prices map[string]map[string]types.PriceByPair, averagedPrices map[string]types.PriceByPair) map[string]int {
+	sources := make(map[string]int)
+	for _, priceByPair := range prices {
+		for _, price := range priceByPair {
+			quoteUSD := fmt.Sprintf("%s/USD", price.Quote)
+			if !price.Stale && (price.Quote == "USD" || averagedPrices[quoteUSD].Price > 0.0) {
+				sources[price.Base]++
+			}
+		}
+	}
+	return sources
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/terra-money/oracle-feeder-go/pkg/types"
+)
+
+// GetReadiness checks that enough providers serve fresh prices and that the
+// required denoms are aggregated from enough fresh prices, as configured by
+// the Readiness config.
+func (m *ProviderManager) GetReadiness(ctx context.Context) []types.ReadyCheck {
+	cfg := m.config.Readiness
+	minTimestamp := uint64(time.Now().Add(-time.Duration(cfg.MaxAge) * time.Second).UnixMilli())
+
+	// exchange -> pair -> price, without the stale and the old prices
+	freshPrices := make(map[string]map[string]types.PriceByPair)
+	var healthy, unhealthy []string
+	for exchange, provider := range m.providers {
+		fresh := make(map[string]types.PriceByPair)
+		for pair, price := range provider.GetPrices() {
+			if !price.Stale && (cfg.MaxAge <= 0 || price.Timestamp >= minTimestamp) {
+				fresh[pair] = price
+			}
+		}
+		if len(fresh) > 0 {
+			freshPrices[exchange] = fresh
+			healthy = append(healthy, exchange)
+		} else {
+			unhealthy = append(unhealthy, exchange)
+		}
+	}
+	sort.Strings(unhealthy)
+
+	providersCheck := types.ReadyCheck{
+		Name:   "providers",
+		Ok:     len(healthy) >= cfg.MinProviders,
+		Detail: fmt.Sprintf("%d of %d providers serve fresh prices, %d required", len(healthy), len(m.providers), cfg.MinProviders),
+	}
+	if len(unhealthy) > 0 {
+		providersCheck.Detail += fmt.Sprintf(", no fresh price from %s", strings.Join(unhealthy, ", "))
+	}
+	checks := []types.ReadyCheck{providersCheck}
+
+	served := make(map[string]bool)
+	for _, price := range m.GetPrices(ctx).Prices {
+		served[strings.ToUpper(price.Denom)] = true
+	}
+	sources := make(map[string]int)
+	for coin, count := range sourcesByCoin(freshPrices, averagePriceByPair(freshPrices)) {
+		sources[strings.ToUpper(coin)] += count
+	}
+	for _, denom := range cfg.Denoms {
+		denom = strings.ToUpper(denom)
+		check := types.ReadyCheck{Name: "denom:" + denom}
+		switch {
+		case !served[denom]:
+			check.Detail = "no price"
+		case sources[denom] < cfg.Quorum:
+			check.Detail = fmt.Sprintf("%d fresh prices, %d required", sources[denom], cfg.Quorum)
+		default:
+			check.Ok = true
+			check.Detail = fmt.Sprintf("%d fresh prices", sources[denom])
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
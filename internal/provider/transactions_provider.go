@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
 	"github.com/tendermint/tmlibs/bech32"
+	"github.com/terra-money/oracle-feeder-go/internal/daemon"
 	"github.com/terra-money/oracle-feeder-go/internal/provider/internal"
 	"github.com/terra-money/oracle-feeder-go/internal/types"
 
@@ -14,7 +16,9 @@ import (
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdktypes "github.com/cosmos/cosmos-sdk/codec/types"
 	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	cryptoTypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
 	"github.com/cosmos/cosmos-sdk/std"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	txTypes "github.com/cosmos/cosmos-sdk/types/tx"
@@ -22,6 +26,8 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/auth/signing"
 	"github.com/cosmos/cosmos-sdk/x/auth/tx"
 	authTypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type TransactionsProvider struct {
@@ -37,11 +43,24 @@ type TransactionsProvider struct {
 	feederType                 types.FeederType
 }
 
+const (
+	// txInclusionTimeout is how long a broadcast transaction can take to be included in a block.
+	txInclusionTimeout = 60 * time.Second
+	txPollInterval     = time.Second
+)
+
+// simulationPubKey stands for the key of a signer whose public key is not
+// known yet, e.g. a multisig which never signed, the simulations do not verify it.
+var simulationPubKey = &secp256k1.PubKey{Key: make([]byte, secp256k1.PubKeySize)}
+
+// NewTransactionsProvider signs with the key of MNEMONIC, the transactions
+// of SIGNER_ADDRESS, e.g. a multisig, can only be exported unsigned.
 func NewTransactionsProvider(
 	feederType types.FeederType,
 ) TransactionsProvider {
-	var mnemonic string
-	if mnemonic = os.Getenv("MNEMONIC"); len(mnemonic) == 0 {
+	mnemonic := os.Getenv("MNEMONIC")
+	signerAddress := os.Getenv("SIGNER_ADDRESS")
+	if len(mnemonic) == 0 && len(signerAddress) == 0 {
 		panic("MNEMONIC env variable is not set!")
 	}
 
@@ -65,13 +84,26 @@ func NewTransactionsProvider(
 		panic("ORACLE_ADDRESS env variable is not set!")
 	}
 
-	privKeyBytes, err := hd.Secp256k1.Derive()(mnemonic, "", "m/44'/330'/0'/0/0")
-	if err != nil {
-		panic(err)
+	// the signers of the decoded transactions are parsed with the account prefix
+	sdk.GetConfig().SetBech32PrefixForAccount("terra", "terrapub")
+
+	var privKey cryptoTypes.PrivKey
+	var address sdk.AccAddress
+	if len(mnemonic) != 0 {
+		privKeyBytes, err := hd.Secp256k1.Derive()(mnemonic, "", "m/44'/330'/0'/0/0")
+		if err != nil {
+			panic(err)
+		}
+		privKey = hd.Secp256k1.Generate()(privKeyBytes)
+		address = sdk.AccAddress(privKey.PubKey().Address())
+	}
+	if len(signerAddress) != 0 {
+		_, signerBytes, err := bech32.DecodeAndConvert(signerAddress)
+		if err != nil {
+			panic(fmt.Sprintf("invalid SIGNER_ADDRESS %s: %v", signerAddress, err))
+		}
+		address = signerBytes
 	}
-
-	privKey := hd.Secp256k1.Generate()(privKeyBytes)
-	address := sdk.AccAddress(privKey.PubKey().Address())
 	return TransactionsProvider{
 		BaseGrpc:                   *internal.NewBaseGrpc(),
 		privKey:                    privKey,
@@ -86,23 +118,66 @@ func NewTransactionsProvider(
 	}
 }
 
-func (p *TransactionsProvider) SubmitAlliancesTransaction(
+// NewBroadcaster only broadcasts the transactions signed offline, which carry
+// their signer and contract, so it only needs NODE_GRPC_URL.
+func NewBroadcaster() TransactionsProvider {
+	var nodeGrpcUrl string
+	if nodeGrpcUrl = os.Getenv("NODE_GRPC_URL"); len(nodeGrpcUrl) == 0 {
+		panic("NODE_GRPC_URL env variable is not set!")
+	}
+
+	// the signers of the decoded transactions are parsed with the account prefix
+	sdk.GetConfig().SetBech32PrefixForAccount("terra", "terrapub")
+
+	return TransactionsProvider{
+		BaseGrpc:    *internal.NewBaseGrpc(),
+		nodeGrpcUrl: nodeGrpcUrl,
+		prefix:      "terra",
+		denom:       "uluna",
+	}
+}
+
+// Address returns the bech32 address of the key signing the transactions.
+func (p *TransactionsProvider) Address() string {
+	address, err := bech32.ConvertAndEncode(p.prefix, p.address)
+	if err != nil {
+		panic(err)
+	}
+	return address
+}
+
+// SimulatedTx is an alliance transaction simulated on chain with its gas
+// and fee set, ready to be signed.
+type SimulatedTx struct {
+	Msg           *wasmtypes.MsgExecuteContract
+	ChainId       string
+	AccountNumber uint64
+	Sequence      uint64
+	GasUsed       uint64
+	GasLimit      uint64
+	Fee           sdk.Coins
+
+	builder  client.TxBuilder
+	txConfig client.TxConfig
+}
+
+// SimulateAlliancesTransaction builds the transaction executing msg on the
+// contract of the feeder type and simulates it, it is not broadcast.
+func (p *TransactionsProvider) SimulateAlliancesTransaction(
 	ctx context.Context,
 	msg []byte,
-) (string, error) {
+) (*SimulatedTx, error) {
 	// Get the bech address and...
 	bech32Addr, err := bech32.ConvertAndEncode(p.prefix, p.address)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	// ... build the messages to be signed
-	msgs := []sdk.Msg{
-		&wasmtypes.MsgExecuteContract{
-			Sender:   bech32Addr,
-			Contract: p.getContractAddress(),
-			Msg:      msg,
-			Funds:    nil,
-		},
+	// ... build the message to be signed
+	executeMsg := &wasmtypes.MsgExecuteContract{
+		Sender:   bech32Addr,
+		Contract: p.getContractAddress(),
+		Msg:      msg,
+		Funds:    nil,
 	}
 	var account authTypes.AccountI
 	txBuilder, txConfig, interfaceRegistry := p.getTxClients()
@@ -110,115 +185,223 @@ func (p *TransactionsProvider) SubmitAlliancesTransaction(
 	// create gRPC connection
 	grpcConn, err := p.BaseGrpc.Connection(ctx, p.nodeGrpcUrl)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer grpcConn.Close()
 
-	fromAddress, err := bech32.ConvertAndEncode(p.prefix, p.address)
-	if err != nil {
-		return "", err
-	}
-
 	authClient := authTypes.NewQueryClient(grpcConn)
 	accRes, err := authClient.Account(ctx, &authTypes.QueryAccountRequest{
-		Address: fromAddress,
+		Address: bech32Addr,
 	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	err = interfaceRegistry.UnpackAny(accRes.Account, &account)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	accSeq := account.GetSequence()
 
-	pubKey := p.privKey.PubKey()
-	signMode := txConfig.SignModeHandler().DefaultMode()
-
-	signerData := signing.SignerData{
-		ChainID:       p.ChainId,
-		AccountNumber: account.GetAccountNumber(),
-		Sequence:      accSeq,
-	}
-	sigData := txsigning.SingleSignatureData{
-		SignMode:  signMode,
-		Signature: nil,
+	pubKey := account.GetPubKey()
+	if p.canSign() {
+		pubKey = p.privKey.PubKey()
+	} else if pubKey == nil {
+		pubKey = simulationPubKey
 	}
 	sigv2 := txsigning.SignatureV2{
 		PubKey:   pubKey,
-		Data:     &sigData,
+		Data:     simulationSignature(pubKey, txConfig.SignModeHandler().DefaultMode()),
 		Sequence: accSeq,
 	}
 
 	// build txn
-	err = txBuilder.SetMsgs(msgs...)
+	err = txBuilder.SetMsgs(executeMsg)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	err = txBuilder.SetSignatures(sigv2)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// simulate transaction to get gas cost and see if it will fail
 	simulateBytes, err := txConfig.TxEncoder()(txBuilder.GetTx())
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	queryClient := txTypes.NewServiceClient(grpcConn)
 	simRes, err := queryClient.Simulate(ctx, &txTypes.SimulateRequest{
 		TxBytes: simulateBytes,
 	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// set the gas needed with some allowance
 	gasUsed := simRes.GetGasInfo().GetGasUsed()
+	gasLimit := uint64(float64(gasUsed) * 1.5)
 
 	// calculate fee
-	fee := sdk.NewIntFromUint64(uint64(float64(gasUsed) * 0.0155 * 1.5))
+	fee := sdk.NewCoins(sdk.NewCoin(p.denom, sdk.NewIntFromUint64(uint64(float64(gasUsed)*0.0155*1.5))))
 
 	// set fee amount from gasused
-	txBuilder.SetFeeAmount(sdk.NewCoins(sdk.NewCoin(p.denom, fee)))
+	txBuilder.SetFeeAmount(fee)
+	txBuilder.SetGasLimit(gasLimit)
+
+	return &SimulatedTx{
+		Msg:           executeMsg,
+		ChainId:       p.ChainId,
+		AccountNumber: account.GetAccountNumber(),
+		Sequence:      accSeq,
+		GasUsed:       gasUsed,
+		GasLimit:      gasLimit,
+		Fee:           fee,
+		builder:       txBuilder,
+		txConfig:      txConfig,
+	}, nil
+}
 
-	txBuilder.SetGasLimit(uint64(float64(gasUsed) * 1.5))
+// simulationSignature is an empty signature of the key, every key of a
+// multisig signing so that the simulation uses the most gas it could.
+func simulationSignature(pubKey cryptoTypes.PubKey, signMode txsigning.SignMode) txsigning.SignatureData {
+	multisigKey, ok := pubKey.(multisig.PubKey)
+	if !ok {
+		return &txsigning.SingleSignatureData{SignMode: signMode}
+	}
+	keys := multisigKey.GetPubKeys()
+	data := multisig.NewMultisig(len(keys))
+	for i, key := range keys {
+		data.BitArray.SetIndex(i, true)
+		data.Signatures = append(data.Signatures, simulationSignature(key, signMode))
+	}
+	return data
+}
+
+// SigningInfo is what the signers of an exported transaction sign it with,
+// the SDK JSON of the unsigned transaction carrying none of it.
+type SigningInfo struct {
+	ChainId       string `json:"chain_id"`
+	Signer        string `json:"signer"`
+	AccountNumber uint64 `json:"account_number"`
+	Sequence      uint64 `json:"sequence"`
+}
+
+// SigningInfo returns the chain, signer, account number and sequence of the transaction.
+func (tx *SimulatedTx) SigningInfo() SigningInfo {
+	return SigningInfo{
+		ChainId:       tx.ChainId,
+		Signer:        tx.Msg.Sender,
+		AccountNumber: tx.AccountNumber,
+		Sequence:      tx.Sequence,
+	}
+}
+
+// JSON encodes the transaction without signature to the SDK JSON, e.g. for
+// the signers of a multisig, who sign it with its SigningInfo.
+func (tx *SimulatedTx) JSON() ([]byte, error) {
+	if err := tx.builder.SetSignatures(); err != nil {
+		return nil, err
+	}
+	return tx.txConfig.TxJSONEncoder()(tx.builder.GetTx())
+}
+
+func (p *TransactionsProvider) SubmitAlliancesTransaction(
+	ctx context.Context,
+	msg []byte,
+) (string, error) {
+	simulatedTx, err := p.SimulateAlliancesTransaction(ctx, msg)
 	if err != nil {
 		return "", err
 	}
+	txBytes, err := p.sign(simulatedTx)
+	if err != nil {
+		return "", err
+	}
+	return p.broadcast(ctx, txBytes)
+}
+
+// canSign tells whether the private key is the one of the signer address.
+func (p *TransactionsProvider) canSign() bool {
+	return p.privKey != nil && p.address.Equals(sdk.AccAddress(p.privKey.PubKey().Address()))
+}
+
+// sign signs the transaction with the private key and encodes it.
+func (p *TransactionsProvider) sign(simulatedTx *SimulatedTx) ([]byte, error) {
+	if !p.canSign() {
+		return nil, fmt.Errorf("MNEMONIC is not the key of %s, export the transaction unsigned instead", p.Address())
+	}
+	txBuilder, txConfig := simulatedTx.builder, simulatedTx.txConfig
+	signMode := txConfig.SignModeHandler().DefaultMode()
+	signerData := signing.SignerData{
+		ChainID:       p.ChainId,
+		AccountNumber: simulatedTx.AccountNumber,
+		Sequence:      simulatedTx.Sequence,
+	}
 
 	// sign the final message with the private key
 	bytesToSign, err := txConfig.SignModeHandler().GetSignBytes(signMode, signerData, txBuilder.GetTx())
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	sig, err := p.privKey.Sign(bytesToSign)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	sigData = txsigning.SingleSignatureData{
-		SignMode:  signMode,
-		Signature: sig,
-	}
-	sigv2 = txsigning.SignatureV2{
-		PubKey:   pubKey,
-		Data:     &sigData,
-		Sequence: accSeq,
+	sigv2 := txsigning.SignatureV2{
+		PubKey: p.privKey.PubKey(),
+		Data: &txsigning.SingleSignatureData{
+			SignMode:  signMode,
+			Signature: sig,
+		},
+		Sequence: simulatedTx.Sequence,
 	}
 	err = txBuilder.SetSignatures(sigv2)
+	if err != nil {
+		return nil, err
+	}
+
+	return txConfig.TxEncoder()(txBuilder.GetTx())
+}
+
+// BroadcastSignedTransaction broadcasts a transaction signed offline, e.g. with the
+// aggregated signatures of a multisig, from its SDK JSON and returns its hash.
+func (p *TransactionsProvider) BroadcastSignedTransaction(ctx context.Context, txJSON []byte) (string, error) {
+	_, txConfig, _ := p.getTxClients()
+	signedTx, err := txConfig.TxJSONDecoder()(txJSON)
+	if err != nil {
+		return "", fmt.Errorf("invalid transaction: %w", err)
+	}
+	sigTx, ok := signedTx.(signing.SigVerifiableTx)
+	if !ok {
+		return "", fmt.Errorf("invalid transaction: %T", signedTx)
+	}
+	signatures, err := sigTx.GetSignaturesV2()
+	if err != nil {
+		return "", fmt.Errorf("invalid signatures: %w", err)
+	}
+	if len(signatures) == 0 || len(signatures) != len(sigTx.GetSigners()) {
+		return "", fmt.Errorf("the transaction has %d signatures for %d signers", len(signatures), len(sigTx.GetSigners()))
+	}
+	txBytes, err := txConfig.TxEncoder()(signedTx)
 	if err != nil {
 		return "", err
 	}
+	return p.broadcast(ctx, txBytes)
+}
 
-	// encode and broadcast transaction
-	txBytes, err := txConfig.TxEncoder()(txBuilder.GetTx())
+// broadcast broadcasts the signed transaction and returns its hash once it is
+// included in a block, so that the next transaction of the account is built
+// with the sequence it incremented.
+func (p *TransactionsProvider) broadcast(ctx context.Context, txBytes []byte) (string, error) {
+	grpcConn, err := p.BaseGrpc.Connection(ctx, p.nodeGrpcUrl)
 	if err != nil {
 		return "", err
 	}
-	// Increase cached account sequence before broadcasting tx
-	bRes, err := queryClient.BroadcastTx(ctx,
+	defer grpcConn.Close()
+
+	bRes, err := txTypes.NewServiceClient(grpcConn).BroadcastTx(ctx,
 		&txTypes.BroadcastTxRequest{
 			Mode:    txTypes.BroadcastMode_BROADCAST_MODE_SYNC,
 			TxBytes: txBytes,
@@ -230,7 +413,52 @@ func (p *TransactionsProvider) SubmitAlliancesTransaction(
 	if bRes.TxResponse.Code != 0 {
 		return "", fmt.Errorf("tx failed with code %d, %s", bRes.TxResponse.Code, bRes.TxResponse.RawLog)
 	}
-	return bRes.TxResponse.TxHash, err
+	return bRes.TxResponse.TxHash, waitForTx(ctx, txTypes.NewServiceClient(grpcConn), bRes.TxResponse.TxHash)
+}
+
+// waitForTx polls the node until the transaction is included in a block,
+// it fails when the transaction failed or is not found in time. The latter
+// is permanent, the transaction could still be included and a new one would
+// be built with a stale sequence or stale delegations.
+func waitForTx(ctx context.Context, client txTypes.ServiceClient, hash string) error {
+	ctx, cancel := context.WithTimeout(ctx, txInclusionTimeout)
+	defer cancel()
+	ticker := time.NewTicker(txPollInterval)
+	defer ticker.Stop()
+	for {
+		res, err := client.GetTx(ctx, &txTypes.GetTxRequest{Hash: hash})
+		switch {
+		case err == nil && res.TxResponse.Code != 0:
+			return fmt.Errorf("tx %s failed with code %d, %s", hash, res.TxResponse.Code, res.TxResponse.RawLog)
+		case err == nil:
+			return nil
+		case status.Code(err) != codes.NotFound && ctx.Err() == nil:
+			return daemon.Permanent(fmt.Errorf("tx %s not found: %w", hash, err))
+		}
+		select {
+		case <-ctx.Done():
+			return daemon.Permanent(fmt.Errorf("tx %s not included in a block: %w", hash, ctx.Err()))
+		case <-ticker.C:
+		}
+	}
+}
+
+// QueryContract queries the state of the contract the feeder type executes.
+func (p *TransactionsProvider) QueryContract(ctx context.Context, query []byte) ([]byte, error) {
+	grpcConn, err := p.BaseGrpc.Connection(ctx, p.nodeGrpcUrl)
+	if err != nil {
+		return nil, err
+	}
+	defer grpcConn.Close()
+
+	res, err := wasmtypes.NewQueryClient(grpcConn).SmartContractState(ctx, &wasmtypes.QuerySmartContractStateRequest{
+		Address:   p.getContractAddress(),
+		QueryData: query,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Data, nil
 }
 
 func (p *TransactionsProvider) getTxClients() (client.TxBuilder, client.TxConfig, sdktypes.InterfaceRegistry) {
@@ -244,6 +472,8 @@ func (p *TransactionsProvider) getTxClients() (client.TxBuilder, client.TxConfig
 
 	authTypes.RegisterLegacyAminoCodec(amino)
 	authTypes.RegisterInterfaces(interfaceRegistry)
+	// the messages are encoded to JSON and decoded from it
+	wasmtypes.RegisterInterfaces(interfaceRegistry)
 
 	txBuilder := txConfig.NewTxBuilder()
 	txBuilder.SetMemo("Alliance Oracle designed by Terra Devs")
@@ -0,0 +1,202 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
+	"github.com/cosmos/cosmos-sdk/std"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	txsigning "github.com/cosmos/cosmos-sdk/types/tx/signing"
+	"github.com/cosmos/cosmos-sdk/x/auth/signing"
+	"github.com/cosmos/cosmos-sdk/x/auth/tx"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tmlibs/bech32"
+	"github.com/terra-money/oracle-feeder-go/internal/daemon"
+	"github.com/terra-money/oracle-feeder-go/internal/provider"
+	"github.com/terra-money/oracle-feeder-go/internal/types"
+	pkgtypes "github.com/terra-money/oracle-feeder-go/pkg/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	chainId       = "pisco-1"
+	accountNumber = 7
+	sequence      = 3
+)
+
+func newTxConfig() client.TxConfig {
+	interfaceRegistry := codectypes.NewInterfaceRegistry()
+	std.RegisterInterfaces(interfaceRegistry)
+	authtypes.RegisterInterfaces(interfaceRegistry)
+	wasmtypes.RegisterInterfaces(interfaceRegistry)
+	return tx.NewTxConfig(codec.NewProtoCodec(interfaceRegistry), tx.DefaultSignModes)
+}
+
+// node answers the account of a multisig, simulates the transactions
+// and accepts the broadcast of those the multisig signed, including them
+// in a block on the second poll, or never when pending.
+type node struct {
+	authtypes.UnimplementedQueryServer
+	txtypes.UnimplementedServiceServer
+	account   *authtypes.BaseAccount
+	txConfig  client.TxConfig
+	simulated sdk.Tx
+	polls     int
+	pending   bool
+}
+
+func (n *node) Account(ctx context.Context, req *authtypes.QueryAccountRequest) (*authtypes.QueryAccountResponse, error) {
+	account, err := codectypes.NewAnyWithValue(n.account)
+	if err != nil {
+		return nil, err
+	}
+	return &authtypes.QueryAccountResponse{Account: account}, nil
+}
+
+func (n *node) Simulate(ctx context.Context, req *txtypes.SimulateRequest) (*txtypes.SimulateResponse, error) {
+	simulated, err := n.txConfig.TxDecoder()(req.TxBytes)
+	if err != nil {
+		return nil, err
+	}
+	n.simulated = simulated
+	return &txtypes.SimulateResponse{GasInfo: &sdk.GasInfo{GasUsed: 100000}}, nil
+}
+
+func (n *node) BroadcastTx(ctx context.Context, req *txtypes.BroadcastTxRequest) (*txtypes.BroadcastTxResponse, error) {
+	signedTx, err := n.txConfig.TxDecoder()(req.TxBytes)
+	if err != nil {
+		return nil, err
+	}
+	signatures, err := signedTx.(signing.SigVerifiableTx).GetSignaturesV2()
+	if err != nil {
+		return nil, err
+	}
+	signerData := signing.SignerData{Address: n.account.Address, ChainID: chainId, AccountNumber: accountNumber, Sequence: sequence}
+	err = n.account.GetPubKey().(multisig.PubKey).VerifyMultisignature(func(mode txsigning.SignMode) ([]byte, error) {
+		return n.txConfig.SignModeHandler().GetSignBytes(mode, signerData, signedTx)
+	}, signatures[0].Data.(*txsigning.MultiSignatureData))
+	if err != nil {
+		return &txtypes.BroadcastTxResponse{TxResponse: &sdk.TxResponse{Code: 4, RawLog: err.Error()}}, nil
+	}
+	return &txtypes.BroadcastTxResponse{TxResponse: &sdk.TxResponse{TxHash: "ABCDEF"}}, nil
+}
+
+func (n *node) GetTx(ctx context.Context, req *txtypes.GetTxRequest) (*txtypes.GetTxResponse, error) {
+	if n.polls++; n.polls < 2 || n.pending {
+		return nil, status.Errorf(codes.NotFound, "tx not found: %s", req.Hash)
+	}
+	return &txtypes.GetTxResponse{TxResponse: &sdk.TxResponse{Height: 10, TxHash: req.Hash}}, nil
+}
+
+func TestMultisigOfflineSigning(t *testing.T) {
+	// GIVEN a 2 of 3 multisig controlling the hub
+	keys := []*secp256k1.PrivKey{secp256k1.GenPrivKey(), secp256k1.GenPrivKey(), secp256k1.GenPrivKey()}
+	pubKeys := []cryptotypes.PubKey{keys[0].PubKey(), keys[1].PubKey(), keys[2].PubKey()}
+	multisigKey := kmultisig.NewLegacyAminoPubKey(2, pubKeys)
+	address := sdk.AccAddress(multisigKey.Address())
+	multisigAddress, err := bech32.ConvertAndEncode("terra", address)
+	require.NoError(t, err)
+
+	txConfig := newTxConfig()
+	n := &node{
+		account:  authtypes.NewBaseAccount(address, multisigKey, accountNumber, sequence),
+		txConfig: txConfig,
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	authtypes.RegisterQueryServer(server, n)
+	txtypes.RegisterServiceServer(server, n)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	t.Setenv("MNEMONIC", "")
+	t.Setenv("SIGNER_ADDRESS", multisigAddress)
+	t.Setenv("NODE_GRPC_URL", listener.Addr().String())
+	t.Setenv("ORACLE_ADDRESS", "terra19cs0qcqpa5n7wlqjlhvfe235kc20g52enwr0d5cdar5zkmza7skqv54070")
+	t.Setenv("ALLIANCE_HUB_CONTRACT_ADDRESS", "terra1q95pe55eea0akft0xezak2s50l4vkkquve5emw7gzw65a7ptdl8qel50ea")
+	t.Setenv("CHAIN_ID", chainId)
+	transactionsProvider := provider.NewTransactionsProvider(types.AllianceUpdateRewards)
+	msg, err := json.Marshal(pkgtypes.MsgUpdateRewards{})
+	require.NoError(t, err)
+
+	// WHEN the transaction is exported unsigned
+	simulatedTx, err := transactionsProvider.SimulateAlliancesTransaction(context.Background(), msg)
+	require.NoError(t, err)
+	unsignedJSON, err := simulatedTx.JSON()
+	require.NoError(t, err)
+
+	// THEN it was simulated with every key of the multisig signing
+	require.Equal(t, multisigAddress, simulatedTx.Msg.Sender)
+	require.Equal(t, uint64(accountNumber), simulatedTx.AccountNumber)
+	require.Equal(t, uint64(sequence), simulatedTx.Sequence)
+	require.Equal(t, uint64(150000), simulatedTx.GasLimit)
+	require.Equal(t, provider.SigningInfo{ChainId: chainId, Signer: multisigAddress, AccountNumber: accountNumber, Sequence: sequence}, simulatedTx.SigningInfo())
+	simulatedSignatures, err := n.simulated.(signing.SigVerifiableTx).GetSignaturesV2()
+	require.NoError(t, err)
+	require.Len(t, simulatedSignatures[0].Data.(*txsigning.MultiSignatureData).Signatures, 3)
+	// and it cannot be signed nor broadcast as is
+	_, err = transactionsProvider.SubmitAlliancesTransaction(context.Background(), msg)
+	require.ErrorContains(t, err, "export the transaction unsigned instead")
+	_, err = transactionsProvider.BroadcastSignedTransaction(context.Background(), unsignedJSON)
+	require.EqualError(t, err, "the transaction has 0 signatures for 1 signers")
+
+	// WHEN two signers sign it offline and their signatures are aggregated
+	unsignedTx, err := txConfig.TxJSONDecoder()(unsignedJSON)
+	require.NoError(t, err)
+	txBuilder, err := txConfig.WrapTxBuilder(unsignedTx)
+	require.NoError(t, err)
+	signerData := signing.SignerData{Address: multisigAddress, ChainID: chainId, AccountNumber: accountNumber, Sequence: sequence}
+	multisigData := multisig.NewMultisig(len(pubKeys))
+	for _, key := range keys[:2] {
+		signBytes, err := txConfig.SignModeHandler().GetSignBytes(txsigning.SignMode_SIGN_MODE_LEGACY_AMINO_JSON, signerData, unsignedTx)
+		require.NoError(t, err)
+		signature, err := key.Sign(signBytes)
+		require.NoError(t, err)
+		require.NoError(t, multisig.AddSignatureFromPubKey(multisigData, &txsigning.SingleSignatureData{
+			SignMode:  txsigning.SignMode_SIGN_MODE_LEGACY_AMINO_JSON,
+			Signature: signature,
+		}, key.PubKey(), pubKeys))
+	}
+	require.NoError(t, txBuilder.SetSignatures(txsigning.SignatureV2{PubKey: multisigKey, Data: multisigData, Sequence: sequence}))
+	signedJSON, err := txConfig.TxJSONEncoder()(txBuilder.GetTx())
+	require.NoError(t, err)
+
+	// THEN the signed transaction is broadcast and included in a block, knowing the node only
+	for _, env := range []string{"SIGNER_ADDRESS", "ORACLE_ADDRESS", "ALLIANCE_HUB_CONTRACT_ADDRESS", "CHAIN_ID"} {
+		t.Setenv(env, "")
+	}
+	broadcaster := provider.NewBroadcaster()
+	txHash, err := broadcaster.BroadcastSignedTransaction(context.Background(), signedJSON)
+	require.NoError(t, err)
+	require.Equal(t, "ABCDEF", txHash)
+	require.Equal(t, 2, n.polls)
+
+	// WHEN the next one is accepted but not included in time
+	n.pending = true
+	ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+	defer cancel()
+	_, err = broadcaster.BroadcastSignedTransaction(ctx, signedJSON)
+
+	// THEN the node was polled until the timeout and the error is not retried,
+	// the transaction could still be included
+	require.ErrorContains(t, err, "tx ABCDEF not included in a block")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.True(t, daemon.IsPermanent(err))
+	require.Greater(t, n.polls, 3)
+}
@@ -0,0 +1,69 @@
+// Package reporting sends the errors and panics of the binaries to Sentry.
+package reporting
+
+import (
+	"os"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// flushTimeout bounds the time spent sending the pending events on exit.
+const flushTimeout = 2 * time.Second
+
+// Init reports to the Sentry dsn with the tags set on every event, e.g. the
+// feeder type and the chain. SENTRY_DSN overrides the dsn and reporting is
+// disabled when neither is set.
+func Init(dsn string, tags map[string]string) error {
+	if envDsn := os.Getenv("SENTRY_DSN"); envDsn != "" {
+		dsn = envDsn
+	}
+	if dsn == "" {
+		return nil
+	}
+	if err := sentry.Init(sentry.ClientOptions{Dsn: dsn}); err != nil {
+		return err
+	}
+	sentry.ConfigureScope(func(scope *sentry.Scope) {
+		for key, value := range tags {
+			if value != "" {
+				scope.SetTag(key, value)
+			}
+		}
+	})
+	return nil
+}
+
+// CaptureError reports the error with the extra tags, e.g. the exchange.
+func CaptureError(err error, tags map[string]string) {
+	if err == nil {
+		return
+	}
+	sentry.WithScope(func(scope *sentry.Scope) {
+		scope.SetTags(tags)
+		sentry.CaptureException(err)
+	})
+}
+
+// Recover reports a panic of the calling goroutine before panicking again,
+// it must be deferred.
+func Recover() {
+	if r := recover(); r != nil {
+		sentry.CurrentHub().Recover(r)
+		Flush()
+		panic(r)
+	}
+}
+
+// Go runs fn in a goroutine whose panic is reported.
+func Go(fn func()) {
+	go func() {
+		defer Recover()
+		fn()
+	}()
+}
+
+// Flush waits for the pending events to be sent.
+func Flush() {
+	sentry.Flush(flushTimeout)
+}
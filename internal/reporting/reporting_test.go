@@ -0,0 +1,45 @@
+package reporting_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/internal/reporting"
+)
+
+func TestReporting(t *testing.T) {
+	// GIVEN a Sentry stub collecting the events
+	events := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		events <- string(body)
+	}))
+	defer server.Close()
+	dsn := strings.Replace(server.URL, "http://", "http://public@", 1) + "/1"
+	require.NoError(t, reporting.Init(dsn, map[string]string{"feeder_type": "alliance-oracle-feeder", "chain": ""}))
+
+	// WHEN an error and a panic are reported
+	reporting.CaptureError(errors.New("connection refused"), map[string]string{"exchange": "binance"})
+	reporting.Flush()
+	require.Panics(t, func() {
+		defer reporting.Recover()
+		panic("annual inflation is zero")
+	})
+
+	// THEN
+	event := <-events
+	require.Contains(t, event, "connection refused")
+	require.Contains(t, event, `"exchange":"binance"`)
+	require.Contains(t, event, `"feeder_type":"alliance-oracle-feeder"`)
+	require.NotContains(t, event, `"chain"`)
+	event = <-events
+	require.Contains(t, event, "annual inflation is zero")
+	require.Contains(t, event, `"feeder_type":"alliance-oracle-feeder"`)
+	require.NotContains(t, event, "binance")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/terra-money/oracle-feeder-go/config"
 	"github.com/terra-money/oracle-feeder-go/internal/restful/internal/astroport"
 	"github.com/terra-money/oracle-feeder-go/internal/restful/internal/bitstamp"
 	"github.com/terra-money/oracle-feeder-go/internal/restful/internal/bittrex"
@@ -11,6 +12,7 @@ import (
 	"github.com/terra-money/oracle-feeder-go/internal/restful/internal/exchangerate"
 	"github.com/terra-money/oracle-feeder-go/internal/restful/internal/fer"
 	"github.com/terra-money/oracle-feeder-go/internal/restful/internal/frankfurter"
+	"github.com/terra-money/oracle-feeder-go/internal/restful/internal/pyth"
 	internal_types "github.com/terra-money/oracle-feeder-go/internal/types"
 )
 
@@ -18,11 +20,11 @@ type restfulClient interface {
 	FetchAndParse(symbols []string, timeout int) (map[string]internal_types.PriceBySymbol, error)
 }
 
-func NewRESTfulClient(exchange string, symbols []string) (restfulClient, error) {
+func NewRESTfulClient(exchange string, config *config.ProviderConfig) (restfulClient, error) {
 	var client restfulClient
 	switch strings.ToLower(exchange) {
 	case "astroport":
-		client = astroport.NewAstroportClient()
+		client = astroport.NewAstroportClient(config.TradeSizes, config.MaxPriceImpact)
 	case "bitstamp":
 		client = bitstamp.NewBitstampClient()
 	case "bittrex":
@@ -35,6 +37,8 @@ func NewRESTfulClient(exchange string, symbols []string) (restfulClient, error)
 		client = fer.NewFerClient()
 	case "frankfurter":
 		client = frankfurter.NewFrankFurterClient()
+	case "pyth":
+		client = pyth.NewPythClient()
 	default:
 		return nil, fmt.Errorf("unknown RESTful exchange: %s", exchange)
 	}
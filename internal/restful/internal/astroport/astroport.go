@@ -7,6 +7,7 @@ import (
 	"log"
 	"math"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
@@ -14,52 +15,100 @@ import (
 	internal_types "github.com/terra-money/oracle-feeder-go/internal/types"
 )
 
+const baseUrl = "https://develop-multichain-api.astroport.fi/router/v2/routes"
+
 type AstroportClient struct {
-	url     string
-	amount  int64
-	chainId string
+	url            string
+	chainId        string
+	tradeSizes     map[string]float64 // unified pair -> amount of base coin
+	maxPriceImpact float64
 }
 
-func NewAstroportClient() *AstroportClient {
+func NewAstroportClient(tradeSizes map[string]float64, maxPriceImpact float64) *AstroportClient {
+	url := baseUrl
+	if envUrl := os.Getenv("ASTROPORT_API_URL"); envUrl != "" {
+		url = envUrl
+	}
 	return &AstroportClient{
-		url:     "https://develop-multichain-api.astroport.fi/router/v2/routes",
-		amount:  1000000,
-		chainId: "phoenix-1",
+		url:            url,
+		chainId:        "phoenix-1",
+		tradeSizes:     tradeSizes,
+		maxPriceImpact: maxPriceImpact,
 	}
 }
 
+// FetchAndParse simulates a swap of the configured trade size for each symbol.
+// Rejected quotes are returned with a zero price so the symbol stops being priced.
 func (p *AstroportClient) FetchAndParse(symbols []string, timeout int) (map[string]internal_types.PriceBySymbol, error) {
-	astroData, err := p.fetchPrices(symbols, timeout)
-	if err != nil {
-		return nil, err
-	}
-	res := p.parseAmounts(astroData)
-	return res, nil
-}
-
-func (p *AstroportClient) fetchPrices(symbols []string, timeout int) (map[string]internal_types.AstroportData, error) {
-	astroData := make(map[string]internal_types.AstroportData)
+	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
+	prices := make(map[string]internal_types.PriceBySymbol)
+	now := uint64(time.Now().UnixMilli())
 	for _, symbol := range symbols {
-		symbolSplit := strings.Split(symbol, "-")
-		data, err := p.queryData(symbolSplit[0], symbolSplit[1])
+		base, quote, err := parser.ParseSymbol("astroport", symbol)
 		if err != nil {
-			return nil, err
+			log.Printf("%v", err)
+			continue
 		}
+		tradeSize := p.tradeSize(base, quote)
 
-		astroData[symbol] = data[0]
+		symbolSplit := strings.Split(symbol, "-")
+		data, err := p.queryData(client, symbolSplit[0], symbolSplit[1], int64(tradeSize*math.Pow10(6)))
 		if err != nil {
-			return nil, err
+			log.Printf("astroport %s: %v", symbol, err)
+			continue
+		}
+		if len(data) == 0 {
+			log.Printf("astroport %s: no route", symbol)
+			continue
+		}
+
+		price := internal_types.PriceBySymbol{
+			Exchange:    "astroport",
+			Symbol:      symbol,
+			Base:        base,
+			Quote:       quote,
+			Timestamp:   now,
+			PriceImpact: data[0].TotalPriceImpact,
+		}
+		if err := p.checkQuote(data[0]); err != nil {
+			log.Printf("astroport %s rejected: %v", symbol, err)
+		} else {
+			price.Price = float64(data[0].AmountOut) / math.Pow10(6) / tradeSize // 6 decimals
 		}
+		prices[symbol] = price
+	}
+	return prices, nil
+}
 
+func (p *AstroportClient) tradeSize(base, quote string) float64 {
+	if size, ok := p.tradeSizes[fmt.Sprintf("%s/%s", base, quote)]; ok && size > 0 {
+		return size
+	}
+	return 1
+}
+
+// checkQuote rejects the routes going through an illiquid
+// pair or whose price impact exceeds the ceiling.
+func (p *AstroportClient) checkQuote(data internal_types.AstroportData) error {
+	if data.Path.Illiquid {
+		return fmt.Errorf("illiquid route")
+	}
+	for _, route := range data.Path.Route {
+		if route.Illiquid {
+			return fmt.Errorf("illiquid pair %s", route.ContractAddr)
+		}
+	}
+	if p.maxPriceImpact > 0 && data.TotalPriceImpact > p.maxPriceImpact {
+		return fmt.Errorf("price impact %f exceeds %f", data.TotalPriceImpact, p.maxPriceImpact)
 	}
-	return astroData, nil
+	return nil
 }
 
-func (p *AstroportClient) queryData(start, end string) (res []internal_types.AstroportData, err error) {
-	urlParams := fmt.Sprintf("?start=%s&end=%s&amount=%d&chainId=%s", start, end, p.amount, p.chainId)
+func (p *AstroportClient) queryData(client *http.Client, start, end string, amount int64) (res []internal_types.AstroportData, err error) {
+	urlParams := fmt.Sprintf("?start=%s&end=%s&amount=%d&chainId=%s", start, end, amount, p.chainId)
 
 	// Send GET request
-	resp, err := http.Get(p.url + urlParams)
+	resp, err := client.Get(p.url + urlParams)
 	if err != nil {
 		return nil, err
 	}
@@ -79,24 +128,3 @@ func (p *AstroportClient) queryData(start, end string) (res []internal_types.Ast
 	// Access parsed data
 	return res, nil
 }
-
-func (p *AstroportClient) parseAmounts(res map[string]internal_types.AstroportData) map[string]internal_types.PriceBySymbol {
-	prices := make(map[string]internal_types.PriceBySymbol)
-	now := uint64(time.Now().UnixMilli())
-	for symbol, value := range res {
-		base, quote, err := parser.ParseSymbol("astroport", symbol)
-		if err == nil {
-			prices[symbol] = internal_types.PriceBySymbol{
-				Exchange:  "astroport",
-				Symbol:    symbol,
-				Base:      base,
-				Quote:     quote,
-				Price:     float64(value.AmountOut) / math.Pow10(6), // 6 decimals
-				Timestamp: now,
-			}
-		} else {
-			log.Printf("%v", err)
-		}
-	}
-	return prices
-}
@@ -0,0 +1,55 @@
+package astroport_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/internal/restful/internal/astroport"
+	internal_types "github.com/terra-money/oracle-feeder-go/internal/types"
+)
+
+const (
+	axlUsdc = "ibc/B3504E092456BA618CC28AC671A71FB08C6CA0FD0BE7C8A5B5A3E2DD933CC9E4"
+	stLuna  = "ibc/08095CEDEA29977C9DD0CE9A48329FDA622C183359D5F90CF04CC4FF80CBE431"
+	ampLuna = "terra1ecgazyd0waaj3g7l9cmy5gulhxkps2gmxu9ghducvuypjq68mq2s5lvsct"
+	bLuna   = "terra17aj4ty4sz4yhgm08na8drc0v03v2jwr3waxcqrwhajj729zhl7zqnpc0ml"
+)
+
+func TestFetchAndParse(t *testing.T) {
+	// GIVEN a router where every coin is worth 2 USDC, the route of
+	// backboneLuna is illiquid and ampLuna suffers a 10% price impact
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		amount, err := strconv.Atoi(r.URL.Query().Get("amount"))
+		require.NoError(t, err)
+		data := internal_types.AstroportData{AmountOut: amount * 2}
+		switch r.URL.Query().Get("start") {
+		case stLuna:
+			require.Equal(t, 500_000_000, amount)
+			data.TotalPriceImpact = 0.001
+		case bLuna:
+			data.Path.Route = []internal_types.Route{{ContractAddr: "terra1pair", Illiquid: true}}
+		case ampLuna:
+			data.TotalPriceImpact = 0.1
+		}
+		_ = json.NewEncoder(w).Encode([]internal_types.AstroportData{data})
+	}))
+	defer server.Close()
+	t.Setenv("ASTROPORT_API_URL", server.URL)
+	client := astroport.NewAstroportClient(map[string]float64{"STLUNA/USDC": 500}, 0.05)
+
+	// WHEN
+	prices, err := client.FetchAndParse([]string{stLuna + "-" + axlUsdc, ampLuna + "-" + axlUsdc, bLuna + "-" + axlUsdc}, 1)
+
+	// THEN rejected quotes are withdrawn with a zero price
+	require.NoError(t, err)
+	require.Len(t, prices, 3)
+	require.InDelta(t, 2, prices[stLuna+"-"+axlUsdc].Price, 1e-9)
+	require.InDelta(t, 0.001, prices[stLuna+"-"+axlUsdc].PriceImpact, 1e-9)
+	require.Zero(t, prices[ampLuna+"-"+axlUsdc].Price)
+	require.InDelta(t, 0.1, prices[ampLuna+"-"+axlUsdc].PriceImpact, 1e-9)
+	require.Zero(t, prices[bLuna+"-"+axlUsdc].Price)
+}
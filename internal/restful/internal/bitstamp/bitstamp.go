@@ -6,7 +6,9 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,10 +22,16 @@ const (
 	numWorkers = 16
 )
 
-type BitstampClient struct{}
+type BitstampClient struct {
+	url string
+}
 
 func NewBitstampClient() *BitstampClient {
-	return &BitstampClient{}
+	url := baseUrl
+	if envUrl := os.Getenv("BITSTAMP_API_URL"); envUrl != "" {
+		url = envUrl
+	}
+	return &BitstampClient{url: url}
 }
 
 func (p *BitstampClient) FetchAndParse(symbols []string, timeout int) (map[string]internal_types.PriceBySymbol, error) {
@@ -42,7 +50,7 @@ func (p *BitstampClient) FetchAndParse(symbols []string, timeout int) (map[strin
 	for i := 0; i < numWorkers; i++ {
 		wg.Add(1)
 		go func() {
-			httpWorker(timeout, symbolCh, &mu, prices)
+			httpWorker(p.url, timeout, symbolCh, &mu, prices)
 			wg.Done()
 		}()
 	}
@@ -50,11 +58,12 @@ func (p *BitstampClient) FetchAndParse(symbols []string, timeout int) (map[strin
 	return prices, nil
 }
 
-func httpWorker(timeout int, symbolCh <-chan string, mu *sync.Mutex, prices map[string]internal_types.PriceBySymbol) {
+func httpWorker(url string, timeout int, symbolCh <-chan string, mu *sync.Mutex, prices map[string]internal_types.PriceBySymbol) {
 	for symbol := range symbolCh {
-		price, err := fetchSymbol(symbol, timeout)
+		price, err := fetchSymbol(url, symbol, timeout)
 		if err != nil {
 			log.Printf("fetchSymbol(%s) failed: %v", symbol, err)
+			continue
 		}
 		mu.Lock()
 		prices[price.Symbol] = *price
@@ -78,8 +87,8 @@ type OHLCVResponse struct {
 }
 
 // API doc: https://www.bitstamp.net/api/#ohlc_data
-func fetchSymbol(symbol string, timeout int) (*internal_types.PriceBySymbol, error) {
-	url := fmt.Sprintf("%s/%s/?step=60&limit=1", baseUrl, symbol)
+func fetchSymbol(baseUrl string, symbol string, timeout int) (*internal_types.PriceBySymbol, error) {
+	url := fmt.Sprintf("%s/%s/?step=60&limit=1", strings.TrimSuffix(baseUrl, "/"), symbol)
 	// log.Println(url)
 	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
 	base, quote, err := parser.ParseSymbol(exchange, symbol)
@@ -122,7 +131,7 @@ func fetchSymbol(symbol string, timeout int) (*internal_types.PriceBySymbol, err
 		Base:      base,
 		Quote:     quote,
 		Price:     (open + close) / 2.0,
-		Timestamp: uint64(timestamp),
+		Timestamp: uint64(timestamp * 1000), // the candles are timestamped in seconds
 	}
 	return price, nil
 }
@@ -0,0 +1,32 @@
+package bitstamp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/internal/restful/internal/bitstamp"
+)
+
+func TestFetchAndParse(t *testing.T) {
+	// GIVEN a candle timestamped in seconds
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/btcusd/", r.URL.Path)
+		_, _ = w.Write([]byte(`{"data":{"pair":"BTC/USD","ohlc":[
+			{"open":"30000","high":"30200","low":"29900","close":"30100","volume":"12.5","timestamp":"1700000000"}
+		]}}`))
+	}))
+	defer server.Close()
+	t.Setenv("BITSTAMP_API_URL", server.URL)
+
+	// WHEN
+	prices, err := bitstamp.NewBitstampClient().FetchAndParse([]string{"btcusd"}, 1)
+
+	// THEN the price is timestamped in milliseconds like the other providers
+	require.NoError(t, err)
+	require.Equal(t, "BTC", prices["btcusd"].Base)
+	require.Equal(t, "USD", prices["btcusd"].Quote)
+	require.InDelta(t, 30050, prices["btcusd"].Price, 1e-9)
+	require.Equal(t, uint64(1700000000000), prices["btcusd"].Timestamp)
+}
@@ -7,6 +7,8 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,39 +17,127 @@ import (
 )
 
 const (
-	baseUrl string = "https://api.coingecko.com/api/v3/simple/price"
+	baseUrl    string = "https://api.coingecko.com/api/v3/simple/price"
+	proBaseUrl string = "https://pro-api.coingecko.com/api/v3/simple/price"
+	// ids per request, a single request with every id gets too long and too expensive
+	batchSize int = 50
+	// backoff after a 429 which does not say when to retry
+	defaultBackoff = time.Minute
+	// age above which a last good price is withdrawn rather than served as stale
+	defaultMaxStaleAge = 10 * time.Minute
 )
 
-type CoingeckoClient struct{}
+type CoingeckoClient struct {
+	url    string
+	apiKey string
+	// last prices fetched successfully, served as stale when a batch fails
+	lastGood    map[string]internal_types.PriceBySymbol
+	maxStaleAge time.Duration
+	// no request is sent before this time once rate limited
+	retryAt time.Time
+}
 
+// NewCoingeckoClient uses the Pro API when COINGECKO_API_KEY is set,
+// COINGECKO_MAX_STALE_AGE is the age in seconds of the oldest last good price served.
 func NewCoingeckoClient() *CoingeckoClient {
-	return &CoingeckoClient{}
+	url := baseUrl
+	apiKey := os.Getenv("COINGECKO_API_KEY")
+	if apiKey != "" {
+		url = proBaseUrl
+	}
+	if envUrl := os.Getenv("COINGECKO_API_URL"); envUrl != "" {
+		url = envUrl
+	}
+	maxStaleAge := defaultMaxStaleAge
+	if seconds, err := strconv.Atoi(os.Getenv("COINGECKO_MAX_STALE_AGE")); err == nil && seconds > 0 {
+		maxStaleAge = time.Duration(seconds) * time.Second
+	}
+	return &CoingeckoClient{
+		url:         url,
+		apiKey:      apiKey,
+		lastGood:    make(map[string]internal_types.PriceBySymbol),
+		maxStaleAge: maxStaleAge,
+	}
 }
 
+// FetchAndParse fetches the prices by batches of ids, the ids of a failed
+// batch keep their last price marked as stale until it gets too old.
 func (p *CoingeckoClient) FetchAndParse(symbols []string, timeout int) (map[string]internal_types.PriceBySymbol, error) {
-	msg, err := fetchPrices(symbols, timeout)
-	if err != nil {
-		return nil, err
+	prices := make(map[string]internal_types.PriceBySymbol)
+	var errs []string
+	for start := 0; start < len(symbols); start += batchSize {
+		end := start + batchSize
+		if end > len(symbols) {
+			end = len(symbols)
+		}
+		batch := symbols[start:end]
+		msg, err := p.fetchPrices(batch, timeout)
+		if err != nil {
+			errs = append(errs, err.Error())
+			for _, symbol := range batch {
+				price, ok := p.lastGood[strings.ToLower(symbol)]
+				if !ok {
+					continue
+				}
+				price.Stale = true
+				// a zero price withdraws the symbol from the provider
+				if time.Since(time.UnixMilli(int64(price.Timestamp))) > p.maxStaleAge {
+					delete(p.lastGood, price.Symbol)
+					price.Price = 0
+				}
+				prices[price.Symbol] = price
+			}
+			continue
+		}
+		for symbol, price := range parseJSON(msg) {
+			prices[symbol] = price
+			p.lastGood[symbol] = price
+		}
+	}
+	if len(errs) > 0 {
+		if len(prices) == 0 {
+			return nil, fmt.Errorf("coingecko: %s", strings.Join(errs, "; "))
+		}
+		log.Printf("coingecko: %s", strings.Join(errs, "; "))
 	}
-	return parseJSON(msg), nil
+	return prices, nil
 }
 
-func fetchPrices(symbols []string, timeout int) (map[string]map[string]float64, error) {
+func (p *CoingeckoClient) fetchPrices(symbols []string, timeout int) (map[string]map[string]float64, error) {
+	if time.Now().Before(p.retryAt) {
+		return nil, fmt.Errorf("rate limited until %s", p.retryAt.Format(time.RFC3339))
+	}
+
 	params := url.Values{}
 	params.Add("vs_currencies", "usd")
 	params.Add("precision", "18")
 	params.Add("ids", strings.Join(symbols, ","))
-	url := fmt.Sprintf("%s?%s", baseUrl, params.Encode())
+	url := fmt.Sprintf("%s?%s", p.url, params.Encode())
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.apiKey != "" {
+		req.Header.Set("x-cg-pro-api-key", p.apiKey)
+	}
 	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
-	resp, err := client.Get(url)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		p.retryAt = time.Now().Add(retryAfter(resp.Header.Get("Retry-After")))
+		return nil, fmt.Errorf("rate limited until %s", p.retryAt.Format(time.RFC3339))
+	}
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s", resp.Status, string(body))
+	}
 	jsonObj := make(map[string]map[string]float64)
 	err = json.Unmarshal(body, &jsonObj)
 	if err != nil {
@@ -56,6 +146,17 @@ func fetchPrices(symbols []string, timeout int) (map[string]map[string]float64,
 	return jsonObj, nil
 }
 
+// retryAfter parses a Retry-After header, given either in seconds or as a date.
+func retryAfter(header string) time.Duration {
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return time.Until(date)
+	}
+	return defaultBackoff
+}
+
 func parseJSON(msg map[string]map[string]float64) map[string]internal_types.PriceBySymbol {
 	prices := make(map[string]internal_types.PriceBySymbol)
 	now := uint64(time.Now().UnixMilli())
@@ -0,0 +1,99 @@
+package coingecko_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/internal/restful/internal/coingecko"
+)
+
+func TestFetchAndParse(t *testing.T) {
+	// GIVEN a Pro API which starts rate limiting after the first round of requests
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "secret", r.Header.Get("x-cg-pro-api-key"))
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		require.LessOrEqual(t, len(ids), 50)
+		if requests.Add(1) > 2 {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		var prices []string
+		for _, id := range ids {
+			if id == "bitcoin" || id == "ethereum" {
+				prices = append(prices, fmt.Sprintf(`"%s":{"usd":100}`, id))
+			}
+		}
+		_, _ = w.Write([]byte("{" + strings.Join(prices, ",") + "}"))
+	}))
+	defer server.Close()
+	t.Setenv("COINGECKO_API_URL", server.URL)
+	t.Setenv("COINGECKO_API_KEY", "secret")
+	client := coingecko.NewCoingeckoClient()
+	symbols := []string{"bitcoin"}
+	for i := 0; i < 55; i++ {
+		symbols = append(symbols, fmt.Sprintf("coin-%d", i))
+	}
+	symbols = append(symbols, "ethereum")
+
+	// WHEN the ids are fetched in two batches
+	prices, err := client.FetchAndParse(symbols, 1)
+
+	// THEN
+	require.NoError(t, err)
+	require.EqualValues(t, 2, requests.Load())
+	require.Len(t, prices, 2)
+	require.InDelta(t, 100, prices["ethereum"].Price, 1e-9)
+	require.False(t, prices["ethereum"].Stale)
+
+	// WHEN rate limited, and again before Retry-After has elapsed
+	for round := 0; round < 2; round++ {
+		stale, err := client.FetchAndParse(symbols, 1)
+
+		// THEN the last good prices are served as stale
+		require.NoError(t, err)
+		require.EqualValues(t, 3, requests.Load())
+		require.Len(t, stale, 2)
+		require.True(t, stale["bitcoin"].Stale)
+		require.Equal(t, prices["bitcoin"].Timestamp, stale["bitcoin"].Timestamp)
+	}
+}
+
+func TestFetchAndParseMaxStaleAge(t *testing.T) {
+	// GIVEN an API failing after the first request
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) > 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"bitcoin":{"usd":100}}`))
+	}))
+	defer server.Close()
+	t.Setenv("COINGECKO_API_URL", server.URL)
+	t.Setenv("COINGECKO_MAX_STALE_AGE", "1")
+	client := coingecko.NewCoingeckoClient()
+	_, err := client.FetchAndParse([]string{"bitcoin"}, 1)
+	require.NoError(t, err)
+
+	// WHEN the last good price gets older than the max age
+	stale, err := client.FetchAndParse([]string{"bitcoin"}, 1)
+	require.NoError(t, err)
+	time.Sleep(1100 * time.Millisecond)
+	expired, err := client.FetchAndParse([]string{"bitcoin"}, 1)
+	require.NoError(t, err)
+
+	// THEN it is served as stale, then withdrawn with a zero price
+	require.True(t, stale["bitcoin"].Stale)
+	require.InDelta(t, 100, stale["bitcoin"].Price, 1e-9)
+	require.Zero(t, expired["bitcoin"].Price)
+	_, err = client.FetchAndParse([]string{"bitcoin"}, 1)
+	require.Error(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
@@ -17,10 +18,16 @@ const (
 	baseUrl  = "https://api.exchangerate.host/latest"
 )
 
-type ExchangeRateClient struct{}
+type ExchangeRateClient struct {
+	url string
+}
 
 func NewExchangeRateClient() *ExchangeRateClient {
-	return &ExchangeRateClient{}
+	url := baseUrl
+	if envUrl := os.Getenv("EXCHANGERATE_API_URL"); envUrl != "" {
+		url = envUrl
+	}
+	return &ExchangeRateClient{url: url}
 }
 
 func (p *ExchangeRateClient) FetchAndParse(symbols []string, timeout int) (map[string]internal_types.PriceBySymbol, error) {
@@ -29,7 +36,7 @@ func (p *ExchangeRateClient) FetchAndParse(symbols []string, timeout int) (map[s
 		items := strings.Split(symbol, "/")
 		baseCurrencies = append(baseCurrencies, items[0])
 	}
-	url := fmt.Sprintf("%s?base=USD&symbols=%s", baseUrl, strings.Join(baseCurrencies, ","))
+	url := fmt.Sprintf("%s?base=USD&symbols=%s", p.url, strings.Join(baseCurrencies, ","))
 	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
 	log.Println(url)
 	resp, err := client.Get(url)
@@ -6,6 +6,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
@@ -17,10 +18,16 @@ const (
 	baseUrl  = "https://api.fer.ee/latest"
 )
 
-type FerClient struct{}
+type FerClient struct {
+	url string
+}
 
 func NewFerClient() *FerClient {
-	return &FerClient{}
+	url := baseUrl
+	if envUrl := os.Getenv("FER_API_URL"); envUrl != "" {
+		url = envUrl
+	}
+	return &FerClient{url: url}
 }
 
 func (p *FerClient) FetchAndParse(symbols []string, timeout int) (map[string]internal_types.PriceBySymbol, error) {
@@ -29,7 +36,7 @@ func (p *FerClient) FetchAndParse(symbols []string, timeout int) (map[string]int
 		items := strings.Split(symbol, "/")
 		baseCurrencies = append(baseCurrencies, items[0])
 	}
-	url := fmt.Sprintf("%s?base=USD&to=%s", baseUrl, strings.Join(baseCurrencies, ","))
+	url := fmt.Sprintf("%s?base=USD&to=%s", p.url, strings.Join(baseCurrencies, ","))
 	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
 	log.Println(url)
 	resp, err := client.Get(url)
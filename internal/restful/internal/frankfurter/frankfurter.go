@@ -6,6 +6,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
@@ -17,10 +18,16 @@ const (
 	baseUrl  = "https://api.frankfurter.APP/latest"
 )
 
-type FrankFurterClient struct{}
+type FrankFurterClient struct {
+	url string
+}
 
 func NewFrankFurterClient() *FrankFurterClient {
-	return &FrankFurterClient{}
+	url := baseUrl
+	if envUrl := os.Getenv("FRANKFURTER_API_URL"); envUrl != "" {
+		url = envUrl
+	}
+	return &FrankFurterClient{url: url}
 }
 
 func (p *FrankFurterClient) FetchAndParse(symbols []string, timeout int) (map[string]internal_types.PriceBySymbol, error) {
@@ -29,7 +36,7 @@ func (p *FrankFurterClient) FetchAndParse(symbols []string, timeout int) (map[st
 		items := strings.Split(symbol, "/")
 		baseCurrencies = append(baseCurrencies, items[0])
 	}
-	url := fmt.Sprintf("%s?from=USD&to=%s", baseUrl, strings.Join(baseCurrencies, ","))
+	url := fmt.Sprintf("%s?from=USD&to=%s", p.url, strings.Join(baseCurrencies, ","))
 	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
 	log.Println(url)
 	resp, err := client.Get(url)
@@ -0,0 +1,112 @@
+package pyth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/terra-money/oracle-feeder-go/internal/parser"
+	internal_types "github.com/terra-money/oracle-feeder-go/internal/types"
+)
+
+const (
+	baseUrl string = "https://hermes.pyth.network/v2/updates/price/latest"
+)
+
+// PythClient reads the latest prices of the Pyth price feeds from a Hermes node.
+type PythClient struct {
+	url string
+}
+
+// Latest price updates.
+//
+// API doc: https://hermes.pyth.network/docs/#/rest/latest_price_updates
+type latestPriceResponse struct {
+	Parsed []struct {
+		Id    string `json:"id"`
+		Price struct {
+			Price       string `json:"price"`
+			Conf        string `json:"conf"`
+			Expo        int    `json:"expo"`
+			PublishTime int64  `json:"publish_time"`
+		} `json:"price"`
+	} `json:"parsed"`
+}
+
+func NewPythClient() *PythClient {
+	url := baseUrl
+	if envUrl := os.Getenv("PYTH_API_URL"); envUrl != "" {
+		url = envUrl
+	}
+	return &PythClient{url: url}
+}
+
+func (p *PythClient) FetchAndParse(symbols []string, timeout int) (map[string]internal_types.PriceBySymbol, error) {
+	msg, err := p.fetchPrices(symbols, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return parseJSON(msg), nil
+}
+
+func (p *PythClient) fetchPrices(symbols []string, timeout int) (*latestPriceResponse, error) {
+	params := url.Values{}
+	for _, symbol := range symbols {
+		params.Add("ids[]", symbol)
+	}
+	params.Add("parsed", "true")
+	url := fmt.Sprintf("%s?%s", p.url, params.Encode())
+	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pyth: %s %s", resp.Status, string(body))
+	}
+	var jsonObj latestPriceResponse
+	err = json.Unmarshal(body, &jsonObj)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonObj, nil
+}
+
+func parseJSON(msg *latestPriceResponse) map[string]internal_types.PriceBySymbol {
+	prices := make(map[string]internal_types.PriceBySymbol)
+	for _, feed := range msg.Parsed {
+		base, quote, err := parser.ParseSymbol("pyth", feed.Id)
+		if err != nil {
+			log.Printf("%v", err)
+			continue
+		}
+		// prices are fixed-point numbers, e.g. 6140993501 with an exponent of -8
+		price, err := strconv.ParseFloat(feed.Price.Price, 64)
+		if err != nil {
+			log.Printf("pyth %s: %v", feed.Id, err)
+			continue
+		}
+		prices[feed.Id] = internal_types.PriceBySymbol{
+			Exchange:  "pyth",
+			Symbol:    feed.Id,
+			Base:      base,
+			Quote:     quote,
+			Price:     price * math.Pow10(feed.Price.Expo),
+			Timestamp: uint64(feed.Price.PublishTime * 1000),
+		}
+	}
+	return prices
+}
@@ -0,0 +1,39 @@
+package pyth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/internal/restful/internal/pyth"
+)
+
+const (
+	btcUsd = "e62df6c8b4a85fe1a67db44dc12de5db330f7ac66b72dc658afcf5ed4f0a4f3b"
+	ethUsd = "ff61491a931112ddf1bd8147cd1b641375f79f5825126d665480874634fd0ace"
+)
+
+func TestFetchAndParse(t *testing.T) {
+	// GIVEN a Hermes node knowing the BTC feed only
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, []string{btcUsd, ethUsd}, r.URL.Query()["ids[]"])
+		_, _ = w.Write([]byte(`{"parsed":[{
+			"id":"` + btcUsd + `",
+			"price":{"price":"3012345000000","conf":"1500000000","expo":-8,"publish_time":1700000000}
+		}]}`))
+	}))
+	defer server.Close()
+	t.Setenv("PYTH_API_URL", server.URL)
+
+	// WHEN
+	prices, err := pyth.NewPythClient().FetchAndParse([]string{btcUsd, ethUsd}, 1)
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, "BTC", prices[btcUsd].Base)
+	require.Equal(t, "USD", prices[btcUsd].Quote)
+	require.InDelta(t, 30123.45, prices[btcUsd].Price, 1e-9)
+	require.Equal(t, uint64(1700000000000), prices[btcUsd].Timestamp)
+}
@@ -0,0 +1,216 @@
+// Package signature authenticates the responses of the price server so that
+// the feeder only submits on chain what the price server actually served.
+//
+// The price server signs "<timestamp>\n<path>\n<as of>\n<stale>\n<body>" of
+// each response, the timestamp being in unix milliseconds and the as of and
+// stale lines the values of the AsOfHeader and StaleHeader headers, empty when
+// unset, and sends the base64 signature and the timestamp in the
+// SignatureHeader and TimestampHeader headers.
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	cosmosed25519 "github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	SignatureHeader = "X-Price-Server-Signature"
+	TimestampHeader = "X-Price-Server-Timestamp"
+	// AsOfHeader and StaleHeader tell when a cached response was computed and
+	// whether it is stale, they are signed along with the body.
+	AsOfHeader  = "X-As-Of"
+	StaleHeader = "X-Stale"
+
+	KeyTypeEd25519   = "ed25519"
+	KeyTypeSecp256k1 = "secp256k1"
+
+	// DefaultMaxAge is how old a signed response can be before the feeder rejects it.
+	DefaultMaxAge = 60 * time.Second
+)
+
+// Signer signs the responses of the price server.
+type Signer struct {
+	key cryptotypes.PrivKey
+}
+
+// NewSigner parses a hex encoded private key, the 32 bytes seed of an
+// ed25519 key or the 32 bytes secret of a secp256k1 key.
+func NewSigner(keyType, hexKey string) (*Signer, error) {
+	bz, err := hex.DecodeString(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	if len(bz) != 32 {
+		return nil, fmt.Errorf("invalid private key: expected 32 bytes, got %d", len(bz))
+	}
+	switch keyType {
+	case "", KeyTypeEd25519:
+		return &Signer{key: &cosmosed25519.PrivKey{Key: ed25519.NewKeyFromSeed(bz)}}, nil
+	case KeyTypeSecp256k1:
+		return &Signer{key: &secp256k1.PrivKey{Key: bz}}, nil
+	default:
+		return nil, fmt.Errorf("unknown key type %s", keyType)
+	}
+}
+
+// NewSignerFromEnv reads the key from PRICE_SERVER_PRIVATE_KEY and
+// PRICE_SERVER_KEY_TYPE, it returns nil when no key is set.
+func NewSignerFromEnv() (*Signer, error) {
+	hexKey := os.Getenv("PRICE_SERVER_PRIVATE_KEY")
+	if hexKey == "" {
+		return nil, nil
+	}
+	return NewSigner(os.Getenv("PRICE_SERVER_KEY_TYPE"), hexKey)
+}
+
+// PubKey returns the hex encoded public key to configure on the feeder.
+func (s *Signer) PubKey() string {
+	return hex.EncodeToString(s.key.PubKey().Bytes())
+}
+
+// Sign returns the base64 signature of the body served on path at timestamp with header.
+func (s *Signer) Sign(timestamp time.Time, path string, header http.Header, body []byte) (string, error) {
+	sig, err := s.key.Sign(message(timestamp.UnixMilli(), path, header, body))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// Middleware buffers the response of each route to sign its body.
+func (s *Signer) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		defer func() { c.Writer = writer.ResponseWriter }()
+		c.Next()
+
+		timestamp := time.Now()
+		header := writer.ResponseWriter.Header()
+		sig, err := s.Sign(timestamp, c.Request.URL.Path, header, writer.body.Bytes())
+		if err != nil {
+			_ = c.Error(err)
+			writer.ResponseWriter.WriteHeader(http.StatusInternalServerError)
+			writer.ResponseWriter.WriteHeaderNow()
+			return
+		}
+		header.Set(TimestampHeader, strconv.FormatInt(timestamp.UnixMilli(), 10))
+		header.Set(SignatureHeader, sig)
+		_, _ = writer.ResponseWriter.Write(writer.body.Bytes())
+	}
+}
+
+// bufferedWriter holds the body back until it is signed.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0
+}
+
+// Verifier checks the signatures of the price server responses.
+type Verifier struct {
+	key    cryptotypes.PubKey
+	maxAge time.Duration
+}
+
+// NewVerifier parses a hex encoded public key, the 32 bytes of an ed25519
+// key or the 33 bytes compressed secp256k1 key.
+func NewVerifier(keyType, hexKey string, maxAge time.Duration) (*Verifier, error) {
+	bz, err := hex.DecodeString(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	var key cryptotypes.PubKey
+	switch keyType {
+	case "", KeyTypeEd25519:
+		if len(bz) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid public key: expected %d bytes, got %d", ed25519.PublicKeySize, len(bz))
+		}
+		key = &cosmosed25519.PubKey{Key: bz}
+	case KeyTypeSecp256k1:
+		if len(bz) != secp256k1.PubKeySize {
+			return nil, fmt.Errorf("invalid public key: expected %d bytes, got %d", secp256k1.PubKeySize, len(bz))
+		}
+		key = &secp256k1.PubKey{Key: bz}
+	default:
+		return nil, fmt.Errorf("unknown key type %s", keyType)
+	}
+	if maxAge == 0 {
+		maxAge = DefaultMaxAge
+	}
+	return &Verifier{key: key, maxAge: maxAge}, nil
+}
+
+// NewVerifierFromEnv reads the key from PRICE_SERVER_PUBLIC_KEY and
+// PRICE_SERVER_KEY_TYPE and the max age in seconds from
+// PRICE_SERVER_SIGNATURE_MAX_AGE, it returns nil when no key is set.
+func NewVerifierFromEnv() (*Verifier, error) {
+	hexKey := os.Getenv("PRICE_SERVER_PUBLIC_KEY")
+	if hexKey == "" {
+		return nil, nil
+	}
+	var maxAge time.Duration
+	if value := os.Getenv("PRICE_SERVER_SIGNATURE_MAX_AGE"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PRICE_SERVER_SIGNATURE_MAX_AGE: %w", err)
+		}
+		maxAge = time.Duration(seconds) * time.Second
+	}
+	return NewVerifier(os.Getenv("PRICE_SERVER_KEY_TYPE"), hexKey, maxAge)
+}
+
+// Verify rejects the unsigned, stale and tampered responses, including
+// the responses whose AsOfHeader or StaleHeader were changed.
+func (v *Verifier) Verify(path string, header http.Header, body []byte) error {
+	sig, timestamp := header.Get(SignatureHeader), header.Get(TimestampHeader)
+	if sig == "" || timestamp == "" {
+		return fmt.Errorf("unsigned response")
+	}
+	millis, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid signature timestamp %s", timestamp)
+	}
+	age := time.Since(time.UnixMilli(millis))
+	if age > v.maxAge || age < -v.maxAge {
+		return fmt.Errorf("stale response signed %s ago", age.Round(time.Second))
+	}
+	bz, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	if !v.key.VerifySignature(message(millis, path, header, body), bz) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+func message(timestamp int64, path string, header http.Header, body []byte) []byte {
+	prefix := fmt.Sprintf("%d\n%s\n%s\n%s\n", timestamp, path, header.Get(AsOfHeader), header.Get(StaleHeader))
+	return append([]byte(prefix), body...)
+}
@@ -0,0 +1,114 @@
+package signature_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/terra-money/oracle-feeder-go/internal/signature"
+)
+
+const privateKey = "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60"
+
+func TestSignedResponses(t *testing.T) {
+	for _, keyType := range []string{signature.KeyTypeEd25519, signature.KeyTypeSecp256k1} {
+		t.Run(keyType, func(t *testing.T) {
+			// GIVEN a price server signing its responses
+			signer, err := signature.NewSigner(keyType, privateKey)
+			require.NoError(t, err)
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(signer.Middleware())
+			r.GET("/alliance/protocol", func(c *gin.Context) {
+				c.Header(signature.AsOfHeader, "2023-06-01T00:00:00Z")
+				c.Header(signature.StaleHeader, "true")
+				c.JSON(http.StatusOK, gin.H{"msg": "update_chains_info"})
+			})
+			server := httptest.NewServer(r)
+			defer server.Close()
+			verifier, err := signature.NewVerifier(keyType, signer.PubKey(), time.Minute)
+			require.NoError(t, err)
+
+			// WHEN
+			res, err := http.Get(server.URL + "/alliance/protocol")
+			require.NoError(t, err)
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			// THEN only the untouched body and cache headers served on that path are accepted
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			require.JSONEq(t, `{"msg":"update_chains_info"}`, string(body))
+			require.NoError(t, verifier.Verify("/alliance/protocol", res.Header, body))
+			require.ErrorContains(t, verifier.Verify("/alliance/protocol", res.Header, []byte(`{"msg":"tampered"}`)), "invalid signature")
+			require.ErrorContains(t, verifier.Verify("/alliance/rebalance", res.Header, body), "invalid signature")
+			require.ErrorContains(t, verifier.Verify("/alliance/protocol", http.Header{}, body), "unsigned response")
+			fresh := res.Header.Clone()
+			fresh.Set(signature.StaleHeader, "false")
+			require.ErrorContains(t, verifier.Verify("/alliance/protocol", fresh, body), "invalid signature")
+			recomputed := res.Header.Clone()
+			recomputed.Set(signature.AsOfHeader, "2023-06-01T00:05:00Z")
+			require.ErrorContains(t, verifier.Verify("/alliance/protocol", recomputed, body), "invalid signature")
+		})
+	}
+}
+
+func TestSignedErrors(t *testing.T) {
+	// GIVEN a middleware registered after the signing one rejecting the request
+	signer, err := signature.NewSigner(signature.KeyTypeEd25519, privateKey)
+	require.NoError(t, err)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(signer.Middleware())
+	r.Use(func(c *gin.Context) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": gin.H{"code": "unauthorized"}})
+	})
+	r.GET("/alliance/protocol", func(c *gin.Context) {})
+	verifier, err := signature.NewVerifier(signature.KeyTypeEd25519, signer.PubKey(), time.Minute)
+	require.NoError(t, err)
+
+	// WHEN
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/alliance/protocol", nil))
+
+	// THEN the rejection is signed
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.NoError(t, verifier.Verify("/alliance/protocol", w.Header(), w.Body.Bytes()))
+}
+
+func TestStaleResponse(t *testing.T) {
+	// GIVEN a response signed two minutes ago
+	signer, err := signature.NewSigner(signature.KeyTypeEd25519, privateKey)
+	require.NoError(t, err)
+	verifier, err := signature.NewVerifier(signature.KeyTypeEd25519, signer.PubKey(), time.Minute)
+	require.NoError(t, err)
+	timestamp := time.Now().Add(-2 * time.Minute)
+	header := http.Header{}
+	sig, err := signer.Sign(timestamp, "/alliance/protocol", header, []byte("{}"))
+	require.NoError(t, err)
+	header.Set(signature.SignatureHeader, sig)
+	header.Set(signature.TimestampHeader, strconv.FormatInt(timestamp.UnixMilli(), 10))
+
+	// WHEN
+	err = verifier.Verify("/alliance/protocol", header, []byte("{}"))
+
+	// THEN
+	require.ErrorContains(t, err, "stale response")
+}
+
+func TestWrongPublicKey(t *testing.T) {
+	// GIVEN a verifier configured with the key of another type
+	signer, err := signature.NewSigner(signature.KeyTypeSecp256k1, privateKey)
+	require.NoError(t, err)
+
+	// WHEN
+	_, err = signature.NewVerifier(signature.KeyTypeEd25519, signer.PubKey(), 0)
+
+	// THEN
+	require.ErrorContains(t, err, "invalid public key")
+}
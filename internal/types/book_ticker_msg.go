@@ -0,0 +1,30 @@
+package types
+
+// BookTickerMsg represents the best bid and ask of an order book.
+type BookTickerMsg struct {
+	Exchange  string  // Exchange name
+	Symbol    string  // Exchange-specific trading symbol
+	Base      string  // Base coin
+	Quote     string  // Quote Coin
+	Timestamp uint64  // Book update time
+	Bid       float64 // best bid price
+	Ask       float64 // best ask price
+	Mid       float64 // mid price between the best bid and ask
+	Spread    float64 // bid-ask spread relative to the mid price
+}
+
+// NewBookTickerMsg derives the mid price and the spread from the best bid and ask.
+func NewBookTickerMsg(exchange, symbol, base, quote string, timestamp uint64, bid, ask float64) *BookTickerMsg {
+	mid := (bid + ask) / 2
+	return &BookTickerMsg{
+		Exchange:  exchange,
+		Symbol:    symbol,
+		Base:      base,
+		Quote:     quote,
+		Timestamp: timestamp,
+		Bid:       bid,
+		Ask:       ask,
+		Mid:       mid,
+		Spread:    (ask - bid) / mid,
+	}
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotReady is returned for a result that has not been computed yet,
+// e.g. right after the price server started, or not for too long.
+var ErrNotReady = errors.New("not computed yet")
+
+// UpstreamError is the failure of a node, contract or API the
+// price server depends on, e.g. a gRPC node URL or a contract address.
+type UpstreamError struct {
+	Upstream string
+	Err      error
+}
+
+func NewUpstreamError(upstream string, err error) *UpstreamError {
+	return &UpstreamError{Upstream: upstream, Err: err}
+}
+
+func (e *UpstreamError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Upstream, e.Err)
+}
+
+func (e *UpstreamError) Unwrap() error {
+	return e.Err
+}
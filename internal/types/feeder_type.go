@@ -38,11 +38,11 @@ func ParseFeederTypeFromString(s string) (FeederType, error) {
 func FromFeederTypeToPriceServerUrl(feederType FeederType) string {
 	switch feederType {
 	case AllianceOracleFeeder:
-		return "/alliance/protocol"
+		return "/v1/alliance/protocol"
 	case AllianceRebalanceFeeder:
-		return "/alliance/rebalance"
+		return "/v1/alliance/rebalance"
 	case AllianceInitialDelegation:
-		return "/alliance/delegations"
+		return "/v1/alliance/delegations"
 	default:
 		return ""
 	}
@@ -8,4 +8,10 @@ type PriceBySymbol struct {
 	Quote     string
 	Price     float64
 	Timestamp uint64
+	// price impact of the simulated swap the price comes from, DEX providers only
+	PriceImpact float64
+	// bid-ask spread relative to the price, order-book mode of websocket providers only
+	Spread float64
+	// last good price served again because fetching a fresh one failed
+	Stale bool
 }
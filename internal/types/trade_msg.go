@@ -0,0 +1,17 @@
+package types
+
+// TradeMsg represents the trades of a symbol pushed in one message.
+type TradeMsg struct {
+	Exchange string  // Exchange name
+	Symbol   string  // Exchange-specific trading symbol
+	Base     string  // Base coin
+	Quote    string  // Quote Coin
+	Trades   []Trade // trades in the order they were executed
+}
+
+// Trade represents a single trade.
+type Trade struct {
+	Timestamp uint64  // Trade time
+	Price     float64 // trade price
+	Volume    float64 // base volume
+}
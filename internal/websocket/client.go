@@ -7,14 +7,19 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/terra-money/oracle-feeder-go/internal/metrics"
+	"github.com/terra-money/oracle-feeder-go/internal/reporting"
 	"github.com/terra-money/oracle-feeder-go/internal/types"
 	"github.com/terra-money/oracle-feeder-go/internal/websocket/internal/binance"
 	"github.com/terra-money/oracle-feeder-go/internal/websocket/internal/bitfinex"
 	"github.com/terra-money/oracle-feeder-go/internal/websocket/internal/bybit"
 	"github.com/terra-money/oracle-feeder-go/internal/websocket/internal/coinbase"
+	"github.com/terra-money/oracle-feeder-go/internal/websocket/internal/cryptocom"
+	"github.com/terra-money/oracle-feeder-go/internal/websocket/internal/gateio"
 	"github.com/terra-money/oracle-feeder-go/internal/websocket/internal/huobi"
 	"github.com/terra-money/oracle-feeder-go/internal/websocket/internal/kraken"
 	"github.com/terra-money/oracle-feeder-go/internal/websocket/internal/kucoin"
+	"github.com/terra-money/oracle-feeder-go/internal/websocket/internal/mexc"
 	"github.com/terra-money/oracle-feeder-go/internal/websocket/internal/okx"
 )
 
@@ -24,43 +29,115 @@ type websocketClient interface {
 	HandleMsg(msg []byte, conn *websocket.Conn) (*types.CandlestickMsg, error)
 }
 
-// SubscribeCandlestick subscribes to the candlestick channel.
-func SubscribeCandlestick(exchange string, symbols []string, stopCh <-chan struct{}) (<-chan *types.CandlestickMsg, error) {
-	var client websocketClient
+// bookTickerClient is implemented by the exchanges which
+// can stream the best bid and ask of their order books.
+type bookTickerClient interface {
+	ConnectAndSubscribeBookTicker(symbols []string) (*websocket.Conn, error)
+	// HandleBookTickerMsg handles websocket messages and returns a BookTickerMsg if possible
+	HandleBookTickerMsg(msg []byte, conn *websocket.Conn) (*types.BookTickerMsg, error)
+}
+
+// tradeClient is implemented by the exchanges which can stream their trades.
+type tradeClient interface {
+	ConnectAndSubscribeTrades(symbols []string) (*websocket.Conn, error)
+	// HandleTradeMsg handles websocket messages and returns a TradeMsg if possible
+	HandleTradeMsg(msg []byte, conn *websocket.Conn) (*types.TradeMsg, error)
+}
+
+func newWebsocketClient(exchange string) (websocketClient, error) {
 	switch strings.ToLower(exchange) {
 	case "binance":
-		client = binance.NewWebsocketClient()
+		return binance.NewWebsocketClient(), nil
 	case "bitfinex":
-		client = bitfinex.NewWebsocketClient()
+		return bitfinex.NewWebsocketClient(), nil
 	case "bybit":
-		client = bybit.NewWebsocketClient()
+		return bybit.NewWebsocketClient(), nil
 	case "coinbase":
-		client = coinbase.NewWebsocketClient()
+		return coinbase.NewWebsocketClient(), nil
+	case "cryptocom":
+		return cryptocom.NewWebsocketClient(), nil
+	case "gateio":
+		return gateio.NewWebsocketClient(), nil
 	case "huobi":
-		client = huobi.NewWebsocketClient()
+		return huobi.NewWebsocketClient(), nil
 	case "kraken":
-		client = kraken.NewWebsocketClient()
+		return kraken.NewWebsocketClient(), nil
 	case "kucoin":
-		client = kucoin.NewWebsocketClient()
+		return kucoin.NewWebsocketClient(), nil
+	case "mexc":
+		return mexc.NewWebsocketClient(), nil
 	case "okx":
-		client = okx.NewWebsocketClient()
+		return okx.NewWebsocketClient(), nil
 	default:
 		return nil, fmt.Errorf("unknown websocket exchange %s", exchange)
 	}
+}
 
-	conn, err := client.ConnectAndSubscribe(symbols)
+// SubscribeCandlestick subscribes to the candlestick channel.
+func SubscribeCandlestick(exchange string, symbols []string, stopCh <-chan struct{}) (<-chan *types.CandlestickMsg, error) {
+	client, err := newWebsocketClient(exchange)
 	if err != nil {
 		return nil, err
 	}
+	return subscribe(exchange, func() (*websocket.Conn, error) {
+		return client.ConnectAndSubscribe(symbols)
+	}, client.HandleMsg, stopCh)
+}
 
-	outCh := make(chan *types.CandlestickMsg)
+// SubscribeBookTicker subscribes to the best bid and ask of the order books.
+func SubscribeBookTicker(exchange string, symbols []string, stopCh <-chan struct{}) (<-chan *types.BookTickerMsg, error) {
+	client, err := newWebsocketClient(exchange)
+	if err != nil {
+		return nil, err
+	}
+	bookClient, ok := client.(bookTickerClient)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support the book ticker mode", exchange)
+	}
+	return subscribe(exchange, func() (*websocket.Conn, error) {
+		return bookClient.ConnectAndSubscribeBookTicker(symbols)
+	}, bookClient.HandleBookTickerMsg, stopCh)
+}
+
+// SubscribeTrades subscribes to the trade channel.
+func SubscribeTrades(exchange string, symbols []string, stopCh <-chan struct{}) (<-chan *types.TradeMsg, error) {
+	client, err := newWebsocketClient(exchange)
+	if err != nil {
+		return nil, err
+	}
+	tradeClient, ok := client.(tradeClient)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support the trades mode", exchange)
+	}
+	return subscribe(exchange, func() (*websocket.Conn, error) {
+		return tradeClient.ConnectAndSubscribeTrades(symbols)
+	}, tradeClient.HandleTradeMsg, stopCh)
+}
+
+// subscribe connects to the exchange and forwards the messages parsed
+// by handle until stopCh is closed, reconnecting whenever reading fails.
+func subscribe[T any](
+	exchange string,
+	connect func() (*websocket.Conn, error),
+	handle func(msg []byte, conn *websocket.Conn) (*T, error),
+	stopCh <-chan struct{},
+) (<-chan *T, error) {
+	exchange = strings.ToLower(exchange)
+	conn, err := connect()
+	if err != nil {
+		return nil, err
+	}
+
+	outCh := make(chan *T)
 
 	go func() {
 		var rawMsg []byte
 		for {
 			select {
 			case <-stopCh:
-				conn.Close()
+				if conn != nil {
+					conn.Close()
+				}
 				close(outCh)
 				return
 			default:
@@ -69,21 +146,28 @@ func SubscribeCandlestick(exchange string, symbols []string, stopCh <-chan struc
 				}
 
 				if err == nil {
-					candlestick, err := client.HandleMsg(rawMsg, conn)
+					msg, err := handleMsg(handle, rawMsg, conn)
 					if err != nil {
 						log.Printf("%v", err)
+						metrics.ProviderFailed(exchange)
 					}
-					if candlestick != nil {
-						outCh <- candlestick
+					if msg != nil {
+						metrics.ProviderUpdated(exchange)
+						outCh <- msg
 					}
 				} else {
-					if websocket.IsCloseError(err) {
-						// reconnect automatically
-						conn, err = client.ConnectAndSubscribe(symbols)
-						if err != nil {
-							log.Printf("%v", err)
-							time.Sleep(3 * time.Second)
-						}
+					// a failed connection keeps returning the same error,
+					// so reconnect automatically whatever the cause was
+					log.Printf("%s connection error: %v", exchange, err)
+					metrics.ProviderReconnected(exchange)
+					if conn != nil {
+						conn.Close()
+					}
+					conn, err = connect()
+					if err != nil {
+						log.Printf("%v", err)
+						reporting.CaptureError(err, map[string]string{"exchange": exchange})
+						time.Sleep(3 * time.Second)
 					}
 				}
 			}
@@ -92,3 +176,15 @@ func SubscribeCandlestick(exchange string, symbols []string, stopCh <-chan struc
 
 	return outCh, nil
 }
+
+// handleMsg wraps the message handler of a client so that a malformed message
+// which breaks the parser is reported as an error instead of crashing the server.
+func handleMsg[T any](handle func(msg []byte, conn *websocket.Conn) (*T, error), msg []byte, conn *websocket.Conn) (result *T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = fmt.Errorf("malformed message %s: %v", string(msg), r)
+		}
+	}()
+	return handle(msg, conn)
+}
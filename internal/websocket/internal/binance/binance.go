@@ -3,8 +3,10 @@ package binance
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/terra-money/oracle-feeder-go/internal/parser"
@@ -16,19 +18,36 @@ const (
 	exchangeName string = "binance"
 )
 
-type WebsocketClient struct{}
+type WebsocketClient struct {
+	url string
+}
 
 func NewWebsocketClient() *WebsocketClient {
-	return &WebsocketClient{}
+	url := websocketUrl
+	if envUrl := os.Getenv("BINANCE_WEBSOCKET_URL"); envUrl != "" {
+		url = envUrl
+	}
+	return &WebsocketClient{url: url}
 }
 
 func (wc *WebsocketClient) ConnectAndSubscribe(symbols []string) (*websocket.Conn, error) {
-	conn, _, err := websocket.DefaultDialer.Dial(websocketUrl, nil)
+	return wc.connectAndSubscribe(generateCommand(symbols, "kline_1m"))
+}
+
+func (wc *WebsocketClient) ConnectAndSubscribeBookTicker(symbols []string) (*websocket.Conn, error) {
+	return wc.connectAndSubscribe(generateCommand(symbols, "bookTicker"))
+}
+
+func (wc *WebsocketClient) ConnectAndSubscribeTrades(symbols []string) (*websocket.Conn, error) {
+	return wc.connectAndSubscribe(generateCommand(symbols, "trade"))
+}
+
+func (wc *WebsocketClient) connectAndSubscribe(command map[string]interface{}) (*websocket.Conn, error) {
+	conn, _, err := websocket.DefaultDialer.Dial(wc.url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	command := generateCommand(symbols)
 	if err := conn.WriteJSON(&command); err != nil {
 		return nil, err
 	}
@@ -49,6 +68,14 @@ func (wc *WebsocketClient) HandleMsg(msg []byte, conn *websocket.Conn) (*types.C
 	return parseCandlestickMsg(msg)
 }
 
+func (wc *WebsocketClient) HandleBookTickerMsg(msg []byte, conn *websocket.Conn) (*types.BookTickerMsg, error) {
+	return parseBookTickerMsg(msg)
+}
+
+func (wc *WebsocketClient) HandleTradeMsg(msg []byte, conn *websocket.Conn) (*types.TradeMsg, error) {
+	return parseTradeMsg(msg)
+}
+
 // Candlestick websocket message.
 //
 // Message format: https://binance-docs.github.io/apidocs/spot/en/#kline-candlestick-streams
@@ -62,16 +89,47 @@ type RawCandlestickMsg struct {
 	} `json:"data"`
 }
 
-// generateCommand generates the candlestick subscription command from specified symbols.
+// Book ticker websocket message.
+//
+// Message format: https://binance-docs.github.io/apidocs/spot/en/#individual-symbol-book-ticker-streams
+type RawBookTickerMsg struct {
+	Stream string `json:"stream"`
+	Data   struct {
+		UpdateId uint64 `json:"u"`
+		Symbol   string `json:"s"`
+		BidPrice string `json:"b"`
+		BidQty   string `json:"B"`
+		AskPrice string `json:"a"`
+		AskQty   string `json:"A"`
+	} `json:"data"`
+}
+
+// Trade websocket message.
 //
-// API doc: https://binance-docs.github.io/apidocs/spot/en/#kline-candlestick-streams
+// Message format: https://binance-docs.github.io/apidocs/spot/en/#trade-streams
+type RawTradeMsg struct {
+	Stream string `json:"stream"`
+	Data   struct {
+		EventTime uint64 `json:"E"`
+		EventType string `json:"e"`
+		Symbol    string `json:"s"`
+		TradeId   uint64 `json:"t"`
+		Price     string `json:"p"`
+		Quantity  string `json:"q"`
+		TradeTime uint64 `json:"T"`
+	} `json:"data"`
+}
+
+// generateCommand generates the subscription command to the given stream of specified symbols.
+//
+// API doc: https://binance-docs.github.io/apidocs/spot/en/#live-subscribing-unsubscribing-to-streams
 //
 // For example:
 // {"id":9527,"method":"SUBSCRIBE","params":["btcusdt@kline_1m","ethusdt@kline_1m"]}
-func generateCommand(symbols []string) map[string]interface{} {
+func generateCommand(symbols []string, stream string) map[string]interface{} {
 	var params []string
 	for _, symbol := range symbols {
-		params = append(params, fmt.Sprintf("%s@kline_1m", strings.ToLower(symbol)))
+		params = append(params, fmt.Sprintf("%s@%s", strings.ToLower(symbol), stream))
 	}
 	return map[string]interface{}{
 		"id":     9527,
@@ -138,3 +196,61 @@ func parseCandlestickMsg(rawMsg []byte) (*types.CandlestickMsg, error) {
 		Vwap:      vwap,
 	}, nil
 }
+
+// parseBookTickerMsg parses a book ticker, which carries no timestamp
+// since Binance pushes it as soon as the best bid or ask changes.
+func parseBookTickerMsg(rawMsg []byte) (*types.BookTickerMsg, error) {
+	var msg RawBookTickerMsg
+	err := json.Unmarshal(rawMsg, &msg)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(msg.Stream, "@bookTicker") {
+		return nil, fmt.Errorf("not a book ticker %s", string(rawMsg))
+	}
+	base, quote, err := parser.ParseSymbol(exchangeName, msg.Data.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	bid, err := strconv.ParseFloat(msg.Data.BidPrice, 64)
+	if err != nil {
+		return nil, err
+	}
+	ask, err := strconv.ParseFloat(msg.Data.AskPrice, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewBookTickerMsg(exchangeName, msg.Data.Symbol, base, quote, uint64(time.Now().UnixMilli()), bid, ask), nil
+}
+
+func parseTradeMsg(rawMsg []byte) (*types.TradeMsg, error) {
+	var msg RawTradeMsg
+	err := json.Unmarshal(rawMsg, &msg)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Data.EventType != "trade" {
+		return nil, fmt.Errorf("not a trade %s", string(rawMsg))
+	}
+	base, quote, err := parser.ParseSymbol(exchangeName, msg.Data.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	price, err := strconv.ParseFloat(msg.Data.Price, 64)
+	if err != nil {
+		return nil, err
+	}
+	volume, err := strconv.ParseFloat(msg.Data.Quantity, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.TradeMsg{
+		Exchange: exchangeName,
+		Symbol:   msg.Data.Symbol,
+		Base:     base,
+		Quote:    quote,
+		Trades:   []types.Trade{{Timestamp: msg.Data.TradeTime, Price: price, Volume: volume}},
+	}, nil
+}
@@ -30,7 +30,7 @@ func NewWebsocketClient() *WebsocketClient {
 //
 // Message format: https://docs.cloud.coinbase.com/exchange/docs/websocket-channels#match
 type RawTradeMsg struct {
-	Type         uint64 `json:"type"`
+	Type         string `json:"type"`
 	TradeId      string `json:"trade_id"`
 	Sequence     string `json:"sequence"`
 	MakerOrderId string `json:"maker_order_id"`
@@ -66,7 +66,36 @@ func (wc *WebsocketClient) ConnectAndSubscribe(symbols []string) (*websocket.Con
 	return conn, nil
 }
 
+// ConnectAndSubscribeTrades subscribes to the same matches channel
+// as ConnectAndSubscribe, which builds its candlesticks from the trades.
+func (wc *WebsocketClient) ConnectAndSubscribeTrades(symbols []string) (*websocket.Conn, error) {
+	return wc.ConnectAndSubscribe(symbols)
+}
+
 func (wc *WebsocketClient) HandleMsg(msg []byte, conn *websocket.Conn) (*types.CandlestickMsg, error) {
+	tradeMsg, err := handleMatchMsg(msg)
+	if tradeMsg == nil {
+		return nil, err
+	}
+	return generateCandleStickMsg(tradeMsg)
+}
+
+func (wc *WebsocketClient) HandleTradeMsg(msg []byte, conn *websocket.Conn) (*types.TradeMsg, error) {
+	tradeMsg, err := handleMatchMsg(msg)
+	if tradeMsg == nil {
+		return nil, err
+	}
+	return &types.TradeMsg{
+		Exchange: tradeMsg.Exchange,
+		Symbol:   tradeMsg.Symbol,
+		Base:     tradeMsg.Base,
+		Quote:    tradeMsg.Quote,
+		Trades:   []types.Trade{{Timestamp: tradeMsg.Timestamp, Price: tradeMsg.Price, Volume: tradeMsg.Volume}},
+	}, nil
+}
+
+// handleMatchMsg returns the trade of a match message, nil for the other messages.
+func handleMatchMsg(msg []byte) (*TradeMsg, error) {
 	resp := make(map[string]interface{})
 	if err := json.Unmarshal(msg, &resp); err != nil {
 		return nil, err
@@ -84,12 +113,7 @@ func (wc *WebsocketClient) HandleMsg(msg []byte, conn *websocket.Conn) (*types.C
 	}
 
 	if typ == "match" || typ == "last_match" {
-		tradeMsg, err := parseTradeMsg(msg)
-		if err != nil {
-			return nil, err
-		}
-		candle, err := generateCandleStickMsg(tradeMsg)
-		return candle, err
+		return parseTradeMsg(msg)
 	}
 	return nil, fmt.Errorf("invalid msg: %s", string(msg))
 }
@@ -0,0 +1,176 @@
+package cryptocom
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/terra-money/oracle-feeder-go/internal/parser"
+	"github.com/terra-money/oracle-feeder-go/internal/types"
+)
+
+const (
+	// API doc: https://exchange-docs.crypto.com/exchange/v1/rest-ws/index.html#websocket-root-endpoints
+	websocketUrl string = "wss://stream.crypto.com/exchange/v1/market"
+	exchangeName string = "cryptocom"
+)
+
+type WebsocketClient struct {
+	url string
+}
+
+func NewWebsocketClient() *WebsocketClient {
+	url := websocketUrl
+	if envUrl := os.Getenv("CRYPTOCOM_WEBSOCKET_URL"); envUrl != "" {
+		url = envUrl
+	}
+	return &WebsocketClient{url: url}
+}
+
+func (wc *WebsocketClient) ConnectAndSubscribe(symbols []string) (*websocket.Conn, error) {
+	conn, _, err := websocket.DefaultDialer.Dial(wc.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// requests sent right after connecting count against a rate
+	// limit based on the connection time, so wait for one second
+	// see https://exchange-docs.crypto.com/exchange/v1/rest-ws/index.html#rate-limits
+	time.Sleep(time.Second)
+
+	command := generateCommand(symbols)
+	if err := conn.WriteJSON(&command); err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (wc *WebsocketClient) HandleMsg(msg []byte, conn *websocket.Conn) (*types.CandlestickMsg, error) {
+	var resp RawCandlestickMsg
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("msg error: %s", string(msg))
+	}
+
+	// the server sends a heartbeat every 30 seconds and
+	// closes the connection if it's not answered within 5 seconds
+	// see https://exchange-docs.crypto.com/exchange/v1/rest-ws/index.html#public-respond-heartbeat
+	if resp.Method == "public/heartbeat" {
+		err := conn.WriteJSON(map[string]interface{}{"id": resp.Id, "method": "public/respond-heartbeat"})
+		return nil, err
+	}
+
+	if resp.Method != "subscribe" {
+		log.Printf("receive msg: %s\n", string(msg))
+		return nil, nil
+	}
+	if resp.Result == nil || resp.Result.Channel != "candlestick" {
+		// subscription acknowledgement
+		return nil, nil
+	}
+
+	return parseCandlestickMsg(resp)
+}
+
+// Candlestick websocket message.
+//
+// Message format: https://exchange-docs.crypto.com/exchange/v1/rest-ws/index.html#candlestick-time_frame-instrument_name
+type RawCandlestickMsg struct {
+	Id     int64  `json:"id"`
+	Method string `json:"method"`
+	Code   int    `json:"code"`
+	Result *struct {
+		InstrumentName string `json:"instrument_name"`
+		Channel        string `json:"channel"`
+		Data           []struct {
+			Open      string `json:"o"`
+			High      string `json:"h"`
+			Low       string `json:"l"`
+			Close     string `json:"c"`
+			Volume    string `json:"v"`
+			Timestamp uint64 `json:"t"`
+		} `json:"data"`
+	} `json:"result"`
+}
+
+// generateCommand generates the candlestick subscription command from specified symbols.
+//
+// API doc: https://exchange-docs.crypto.com/exchange/v1/rest-ws/index.html#candlestick-time_frame-instrument_name
+//
+// For example:
+// {"id":9527,"method":"subscribe","params":{"channels":["candlestick.1m.BTC_USDT"]},"nonce":1587523073344}
+func generateCommand(symbols []string) map[string]interface{} {
+	var channels []string
+	for _, symbol := range symbols {
+		channels = append(channels, fmt.Sprintf("candlestick.1m.%s", symbol))
+	}
+	return map[string]interface{}{
+		"id":     9527,
+		"method": "subscribe",
+		"params": map[string]interface{}{
+			"channels": channels,
+		},
+		"nonce": time.Now().UnixMilli(),
+	}
+}
+
+func parseCandlestickMsg(msg RawCandlestickMsg) (*types.CandlestickMsg, error) {
+	data := msg.Result.Data
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no data for %s", msg.Result.InstrumentName)
+	}
+	// candles are sorted by time, the last one is the current candle
+	candle := data[len(data)-1]
+
+	symbol := msg.Result.InstrumentName
+	base, quote, err := parser.ParseSymbol(exchangeName, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	open, err := strconv.ParseFloat(candle.Open, 64)
+	if err != nil {
+		return nil, err
+	}
+	high, err := strconv.ParseFloat(candle.High, 64)
+	if err != nil {
+		return nil, err
+	}
+	low, err := strconv.ParseFloat(candle.Low, 64)
+	if err != nil {
+		return nil, err
+	}
+	close, err := strconv.ParseFloat(candle.Close, 64)
+	if err != nil {
+		return nil, err
+	}
+	baseVolume, err := strconv.ParseFloat(candle.Volume, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	// the candles have no quote volume
+	vwap := (open + close) / 2.0
+
+	return &types.CandlestickMsg{
+		Exchange:  exchangeName,
+		Symbol:    symbol,
+		Base:      base,
+		Quote:     quote,
+		Timestamp: candle.Timestamp,
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+		Volume:    baseVolume,
+		Vwap:      vwap,
+	}, nil
+}
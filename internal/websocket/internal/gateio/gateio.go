@@ -0,0 +1,200 @@
+package gateio
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/terra-money/oracle-feeder-go/internal/parser"
+	"github.com/terra-money/oracle-feeder-go/internal/types"
+)
+
+const (
+	// API doc: https://www.gate.io/docs/developers/apiv4/ws/en/
+	websocketUrl string = "wss://api.gateio.ws/ws/v4/"
+	exchangeName string = "gateio"
+)
+
+type WebsocketClient struct {
+	url string
+}
+
+func NewWebsocketClient() *WebsocketClient {
+	url := websocketUrl
+	if envUrl := os.Getenv("GATEIO_WEBSOCKET_URL"); envUrl != "" {
+		url = envUrl
+	}
+	return &WebsocketClient{url: url}
+}
+
+func (wc *WebsocketClient) ConnectAndSubscribe(symbols []string) (*websocket.Conn, error) {
+	conn, _, err := websocket.DefaultDialer.Dial(wc.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, symbol := range symbols {
+		command := generateCommand(symbol)
+		if err := conn.WriteJSON(&command); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	// send ping per 15 seconds until the connection is closed, e.g. on reconnect
+	// see https://www.gate.io/docs/developers/apiv4/ws/en/#application-ping-pong
+	go func() {
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			pingCommand := generatePingCommand()
+			if err := conn.WriteJSON(&pingCommand); err != nil {
+				log.Printf("%s ping stopped: %v", exchangeName, err)
+				return
+			}
+		}
+	}()
+
+	return conn, nil
+}
+
+func (wc *WebsocketClient) HandleMsg(msg []byte, conn *websocket.Conn) (*types.CandlestickMsg, error) {
+	var resp RawCandlestickMsg
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Error != nil {
+		return nil, fmt.Errorf("msg error: %s", string(msg))
+	}
+	if resp.Channel == "spot.pong" || resp.Event == "subscribe" {
+		return nil, nil
+	}
+	if resp.Channel != "spot.candlesticks" || resp.Event != "update" {
+		log.Printf("receive msg: %s\n", string(msg))
+		return nil, nil
+	}
+
+	return parseCandlestickMsg(resp)
+}
+
+// Candlestick websocket message.
+//
+// Message format: https://www.gate.io/docs/developers/apiv4/ws/en/#candlesticks-channel
+type RawCandlestickMsg struct {
+	Time    int64  `json:"time"`
+	TimeMs  int64  `json:"time_ms"`
+	Channel string `json:"channel"`
+	Event   string `json:"event"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+	Result struct {
+		Timestamp   string `json:"t"`
+		QuoteVolume string `json:"v"`
+		Close       string `json:"c"`
+		High        string `json:"h"`
+		Low         string `json:"l"`
+		Open        string `json:"o"`
+		Name        string `json:"n"`
+		BaseVolume  string `json:"a"`
+	} `json:"result"`
+}
+
+// generateCommand generates the candlestick subscription command of one symbol.
+//
+// API doc: https://www.gate.io/docs/developers/apiv4/ws/en/#candlesticks-channel
+//
+// For example:
+// {"time":1606292218,"channel":"spot.candlesticks","event":"subscribe","payload":["1m","BTC_USDT"]}
+func generateCommand(symbol string) map[string]interface{} {
+	return map[string]interface{}{
+		"time":    time.Now().Unix(),
+		"channel": "spot.candlesticks",
+		"event":   "subscribe",
+		"payload": []string{"1m", symbol},
+	}
+}
+
+// generatePingCommand generates the application level ping command.
+//
+// API doc: https://www.gate.io/docs/developers/apiv4/ws/en/#application-ping-pong
+//
+// For example:
+// {"time":1606292218,"channel":"spot.ping"}
+func generatePingCommand() map[string]interface{} {
+	return map[string]interface{}{
+		"time":    time.Now().Unix(),
+		"channel": "spot.ping",
+	}
+}
+
+func parseCandlestickMsg(msg RawCandlestickMsg) (*types.CandlestickMsg, error) {
+	// name is formatted as <interval>_<symbol>, e.g. 1m_BTC_USDT
+	items := strings.SplitN(msg.Result.Name, "_", 2)
+	if len(items) != 2 {
+		return nil, fmt.Errorf("invalid name %s", msg.Result.Name)
+	}
+	symbol := items[1]
+
+	base, quote, err := parser.ParseSymbol(exchangeName, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	open, err := strconv.ParseFloat(msg.Result.Open, 64)
+	if err != nil {
+		return nil, err
+	}
+	high, err := strconv.ParseFloat(msg.Result.High, 64)
+	if err != nil {
+		return nil, err
+	}
+	low, err := strconv.ParseFloat(msg.Result.Low, 64)
+	if err != nil {
+		return nil, err
+	}
+	close, err := strconv.ParseFloat(msg.Result.Close, 64)
+	if err != nil {
+		return nil, err
+	}
+	baseVolume, err := strconv.ParseFloat(msg.Result.BaseVolume, 64)
+	if err != nil {
+		return nil, err
+	}
+	quoteVolume, err := strconv.ParseFloat(msg.Result.QuoteVolume, 64)
+	if err != nil {
+		return nil, err
+	}
+	vwap := 0.0
+	if baseVolume == 0.0 || quoteVolume == 0.0 {
+		vwap = (open + close) / 2.0
+	} else {
+		vwap = quoteVolume / baseVolume
+	}
+
+	timestamp := uint64(msg.TimeMs)
+	if timestamp == 0 {
+		timestamp = uint64(msg.Time * 1e3)
+	}
+
+	return &types.CandlestickMsg{
+		Exchange:  exchangeName,
+		Symbol:    symbol,
+		Base:      base,
+		Quote:     quote,
+		Timestamp: timestamp,
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+		Volume:    baseVolume,
+		Vwap:      vwap,
+	}, nil
+}
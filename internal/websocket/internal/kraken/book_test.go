@@ -0,0 +1,111 @@
+package kraken
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBookMsg(t *testing.T) {
+	tests := []struct {
+		name string
+		msgs []string
+		bid  float64
+		ask  float64
+		err  bool
+	}{
+		{
+			name: "snapshot",
+			msgs: []string{
+				`[0,{"as":[["30010.0","1.0","1700000000.1"],["30020.0","2.0","1700000000.1"]],"bs":[["30000.0","1.0","1700000000.1"],["29990.0","2.0","1700000000.1"]]},"book-10","XBT/USD"]`,
+			},
+			bid: 30000,
+			ask: 30010,
+		},
+		{
+			name: "delta",
+			msgs: []string{
+				`[0,{"as":[["30010.0","1.0","1700000000.1"]],"bs":[["30000.0","1.0","1700000000.1"]]},"book-10","XBT/USD"]`,
+				`[0,{"a":[["30005.0","0.5","1700000001.1"]]},{"b":[["30002.0","0.4","1700000001.2"]],"c":"974942666"},"book-10","XBT/USD"]`,
+			},
+			bid: 30002,
+			ask: 30005,
+		},
+		{
+			name: "zero level update",
+			msgs: []string{
+				`[0,{"as":[["30010.0","1.0","1700000000.1"],["30020.0","2.0","1700000000.1"]],"bs":[["30000.0","1.0","1700000000.1"],["29990.0","2.0","1700000000.1"]]},"book-10","XBT/USD"]`,
+				`[0,{"a":[["30010.0","0.00000000","1700000001.1"]],"c":"974942666"},"book-10","XBT/USD"]`,
+				`[0,{"b":[["30000.0","0.00000000","1700000001.2"]],"c":"974942666"},"book-10","XBT/USD"]`,
+			},
+			bid: 29990,
+			ask: 30020,
+		},
+		{
+			name: "out of order update",
+			msgs: []string{
+				`[0,{"as":[["30010.0","1.0","1700000000.1"],["30020.0","2.0","1700000000.1"]],"bs":[["30000.0","1.0","1700000000.1"]]},"book-10","XBT/USD"]`,
+				`[0,{"a":[["30010.0","0.00000000","1700000002.1"]],"c":"974942666"},"book-10","XBT/USD"]`,
+				`[0,{"a":[["30010.0","3.0","1700000002.1"]],"c":"974942666"},"book-10","XBT/USD"]`,
+				`[0,{"a":[["30010.0","0.00000000","1700000001.1"]],"c":"974942666"},"book-10","XBT/USD"]`,
+			},
+			bid: 30000,
+			ask: 30010,
+		},
+		{
+			name: "update before snapshot",
+			msgs: []string{
+				`[0,{"a":[["30010.0","1.0","1700000000.1"]],"c":"974942666"},"book-10","XBT/USD"]`,
+			},
+			err: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// GIVEN a snapshot of the book followed by its updates
+			wc := &WebsocketClient{books: make(map[string]*book)}
+
+			// WHEN they are applied in the order received
+			var bid, ask float64
+			var err error
+			for _, msg := range test.msgs {
+				ticker, msgErr := wc.HandleBookTickerMsg([]byte(msg), nil)
+				if msgErr != nil {
+					err = msgErr
+					break
+				}
+				bid, ask = ticker.Bid, ticker.Ask
+			}
+
+			// THEN the best bid and ask are those of the local book
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.bid, bid)
+			require.Equal(t, test.ask, ask)
+		})
+	}
+}
+
+func TestParseBookMsgDepth(t *testing.T) {
+	// GIVEN a full book of ten asks
+	wc := &WebsocketClient{books: make(map[string]*book)}
+	_, err := wc.HandleBookTickerMsg([]byte(`[0,{"as":[
+		["30001.0","1.0","1700000000.1"],["30002.0","1.0","1700000000.1"],["30003.0","1.0","1700000000.1"],
+		["30004.0","1.0","1700000000.1"],["30005.0","1.0","1700000000.1"],["30006.0","1.0","1700000000.1"],
+		["30007.0","1.0","1700000000.1"],["30008.0","1.0","1700000000.1"],["30009.0","1.0","1700000000.1"],
+		["30010.0","1.0","1700000000.1"]],"bs":[["30000.0","1.0","1700000000.1"]]},"book-10","XBT/USD"]`), nil)
+	require.NoError(t, err)
+
+	// WHEN a better ask pushes the worst one out of the depth
+	_, err = wc.HandleBookTickerMsg([]byte(`[0,{"a":[["30000.5","1.0","1700000001.1"]],"c":"974942666"},"book-10","XBT/USD"]`), nil)
+	require.NoError(t, err)
+
+	// THEN the worst ask is dropped from the local book
+	asks := wc.books["XBT/USD"].asks
+	require.Len(t, asks, bookDepth)
+	require.Contains(t, asks, "30000.5")
+	require.NotContains(t, asks, "30010.0")
+}
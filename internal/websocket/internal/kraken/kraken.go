@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -15,16 +18,61 @@ import (
 const (
 	websocketUrl string = "wss://ws.kraken.com"
 	exchangeName string = "kraken"
+	// number of price levels of the books kept locally
+	bookDepth int = 10
 )
 
-type WebsocketClient struct{}
+type WebsocketClient struct {
+	url string
+	// local copy of the order book of each pair, only read
+	// and written by the goroutine consuming the connection
+	books map[string]*book
+}
+
+// book maps the prices of each side of an order book to their level.
+type book struct {
+	asks map[string]level
+	bids map[string]level
+}
+
+// level is the volume at a price and the time it was last updated at.
+type level struct {
+	volume float64
+	time   float64
+}
 
 func NewWebsocketClient() *WebsocketClient {
-	return &WebsocketClient{}
+	url := websocketUrl
+	if envUrl := os.Getenv("KRAKEN_WEBSOCKET_URL"); envUrl != "" {
+		url = envUrl
+	}
+	return &WebsocketClient{url: url}
 }
 
 func (wc *WebsocketClient) ConnectAndSubscribe(symbols []string) (*websocket.Conn, error) {
-	conn, _, err := websocket.DefaultDialer.Dial(websocketUrl, nil)
+	return wc.connectAndSubscribe(generateCommand(symbols, map[string]interface{}{
+		"interval": 1,
+		"name":     "ohlc",
+	}))
+}
+
+func (wc *WebsocketClient) ConnectAndSubscribeBookTicker(symbols []string) (*websocket.Conn, error) {
+	// the books are rebuilt from the snapshots sent on subscription
+	wc.books = make(map[string]*book)
+	return wc.connectAndSubscribe(generateCommand(symbols, map[string]interface{}{
+		"depth": bookDepth,
+		"name":  "book",
+	}))
+}
+
+func (wc *WebsocketClient) ConnectAndSubscribeTrades(symbols []string) (*websocket.Conn, error) {
+	return wc.connectAndSubscribe(generateCommand(symbols, map[string]interface{}{
+		"name": "trade",
+	}))
+}
+
+func (wc *WebsocketClient) connectAndSubscribe(command map[string]interface{}) (*websocket.Conn, error) {
+	conn, _, err := websocket.DefaultDialer.Dial(wc.url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -42,10 +90,6 @@ func (wc *WebsocketClient) ConnectAndSubscribe(symbols []string) (*websocket.Con
 		return nil, fmt.Errorf("Connect error: %v\n", resp)
 	}
 
-	command, err := generateCommand(symbols)
-	if err != nil {
-		return nil, err
-	}
 	if err := conn.WriteJSON(&command); err != nil {
 		return nil, err
 	}
@@ -57,35 +101,53 @@ func (wc *WebsocketClient) HandleMsg(msg []byte, conn *websocket.Conn) (*types.C
 	if strings.HasPrefix(string(msg), "[") {
 		return parseCandlestickMsg(msg)
 	}
+	return nil, handleEvent(msg, conn)
+}
+
+func (wc *WebsocketClient) HandleBookTickerMsg(msg []byte, conn *websocket.Conn) (*types.BookTickerMsg, error) {
+	if strings.HasPrefix(string(msg), "[") {
+		return wc.parseBookMsg(msg)
+	}
+	return nil, handleEvent(msg, conn)
+}
+
+func (wc *WebsocketClient) HandleTradeMsg(msg []byte, conn *websocket.Conn) (*types.TradeMsg, error) {
+	if strings.HasPrefix(string(msg), "[") {
+		return parseTradeMsg(msg)
+	}
+	return nil, handleEvent(msg, conn)
+}
+
+// handleEvent answers heartbeats and checks the subscription status.
+func handleEvent(msg []byte, conn *websocket.Conn) error {
 	if strings.HasPrefix(string(msg), "{") {
 		resp := make(map[string]interface{})
 		if err := json.Unmarshal(msg, &resp); err != nil {
-			return nil, err
+			return err
 		}
 		event, ok := resp["event"].(string)
 		if !ok {
-			return nil, fmt.Errorf("Invalid msg: %s", string(msg))
+			return fmt.Errorf("Invalid msg: %s", string(msg))
 		}
 		if event == "heartbeat" {
-			err := conn.WriteJSON(map[string]interface{}{"event": "ping", "reqid": 9527})
-			return nil, err
+			return conn.WriteJSON(map[string]interface{}{"event": "ping", "reqid": 9527})
 		}
 		if event == "subscriptionStatus" {
 			status, ok := resp["status"].(string)
 			if !ok || status == "error" {
-				return nil, fmt.Errorf("Subscription error: %v\n", string(msg))
+				return fmt.Errorf("Subscription error: %v\n", string(msg))
 			}
-			return nil, nil
+			return nil
 		}
 		if event == "pong" {
-			return nil, nil
+			return nil
 		}
 	}
 	log.Printf("Unrecognized msg: %v\n", string(msg))
-	return nil, nil
+	return nil
 }
 
-// generateCommand generates the candlestick subscription command from specified symbols.
+// generateCommand generates the subscription command to the given channel of specified symbols.
 //
 // API doc: https://docs.kraken.com/websockets/#message-subscribe
 //
@@ -94,19 +156,15 @@ func (wc *WebsocketClient) HandleMsg(msg []byte, conn *websocket.Conn) (*types.C
 //
 // For example:
 // {"event": "subscribe","pair": ["XBT/EUR"],"subscription": {"interval": 5,"name": "ohlc"}}
-func generateCommand(symbols []string) (map[string]interface{}, error) {
+func generateCommand(symbols []string, subscription map[string]interface{}) map[string]interface{} {
 	var topics []string
 	topics = append(topics, symbols...)
 
-	subscription := map[string]interface{}{
-		"interval": 1,
-		"name":     "ohlc",
-	}
 	return map[string]interface{}{
 		"event":        "subscribe",
 		"pair":         topics,
 		"subscription": subscription,
-	}, nil
+	}
 }
 
 // https://docs.kraken.com/websockets/#message-ohlc
@@ -182,3 +240,194 @@ func parseCandlestickMsg(rawMsg []byte) (*types.CandlestickMsg, error) {
 		Vwap:      vwap,
 	}, nil
 }
+
+// parseBookMsg applies a snapshot or an update of a book to its local
+// copy and returns the best bid and ask once both sides are known.
+//
+// API doc: https://docs.kraken.com/websockets/#message-book
+//
+// For example:
+// [0,{"as":[["5541.30000","2.50700000","1534614248.123678"]],"bs":[["5541.20000","1.52900000","1534614248.765567"]]},"book-10","XBT/USD"]
+// [1234,{"a":[["5541.30000","0.00000000","1534614335.345903"]]},{"b":[["5541.10000","0.40100000","1534614335.345903"]],"c":"974942666"},"book-10","XBT/USD"]
+func (wc *WebsocketClient) parseBookMsg(rawMsg []byte) (*types.BookTickerMsg, error) {
+	var arr []json.RawMessage
+	if err := json.Unmarshal(rawMsg, &arr); err != nil {
+		return nil, fmt.Errorf("Invalid msg %s", string(rawMsg))
+	}
+	if len(arr) != 4 && len(arr) != 5 {
+		return nil, fmt.Errorf("Invalid msg %s", string(rawMsg))
+	}
+	var name, symbol string
+	if err := json.Unmarshal(arr[len(arr)-2], &name); err != nil {
+		return nil, fmt.Errorf("Invalid msg %s", string(rawMsg))
+	}
+	if err := json.Unmarshal(arr[len(arr)-1], &symbol); err != nil {
+		return nil, fmt.Errorf("Invalid msg %s", string(rawMsg))
+	}
+	if !strings.HasPrefix(name, "book") {
+		return nil, fmt.Errorf("Not book %s", string(rawMsg))
+	}
+
+	base, quote, err := parser.ParseSymbol(exchangeName, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var timestamp float64
+	for _, raw := range arr[1 : len(arr)-2] {
+		var sides map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &sides); err != nil {
+			return nil, fmt.Errorf("Invalid msg %s", string(rawMsg))
+		}
+		for side := range sides {
+			if side == "as" || side == "bs" {
+				// a snapshot replaces the whole book
+				wc.books[symbol] = &book{asks: make(map[string]level), bids: make(map[string]level)}
+			}
+		}
+		b, ok := wc.books[symbol]
+		if !ok {
+			return nil, fmt.Errorf("book update before snapshot %s", string(rawMsg))
+		}
+		for side, rawLevels := range sides {
+			var levels map[string]level
+			switch side {
+			case "as", "a":
+				levels = b.asks
+			case "bs", "b":
+				levels = b.bids
+			default:
+				// checksum
+				continue
+			}
+			var entries [][]string
+			if err := json.Unmarshal(rawLevels, &entries); err != nil {
+				return nil, fmt.Errorf("Invalid levels %s", string(rawMsg))
+			}
+			for _, entry := range entries {
+				if len(entry) < 3 {
+					return nil, fmt.Errorf("Invalid level %s", string(rawMsg))
+				}
+				volume, err := strconv.ParseFloat(entry[1], 64)
+				if err != nil {
+					return nil, err
+				}
+				levelTime, err := strconv.ParseFloat(entry[2], 64)
+				if err != nil {
+					return nil, err
+				}
+				timestamp = math.Max(timestamp, levelTime)
+				if current, ok := levels[entry[0]]; ok && current.time > levelTime {
+					// out of order, the level was updated since
+					continue
+				}
+				if volume == 0 {
+					delete(levels, entry[0])
+				} else {
+					levels[entry[0]] = level{volume: volume, time: levelTime}
+				}
+			}
+		}
+	}
+
+	b := wc.books[symbol]
+	asks, err := truncate(b.asks, false)
+	if err != nil {
+		return nil, err
+	}
+	bids, err := truncate(b.bids, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(asks) == 0 || len(bids) == 0 {
+		return nil, nil
+	}
+	return types.NewBookTickerMsg(exchangeName, symbol, base, quote, uint64(timestamp*1e3), bids[0], asks[0]), nil
+}
+
+// truncate drops the levels beyond the depth of the book, which Kraken
+// does not remove explicitly, and returns the remaining prices best first.
+func truncate(levels map[string]level, descending bool) ([]float64, error) {
+	prices := make([]float64, 0, len(levels))
+	keys := make(map[float64]string, len(levels))
+	for key := range levels {
+		price, err := strconv.ParseFloat(key, 64)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, price)
+		keys[price] = key
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		if descending {
+			return prices[i] > prices[j]
+		}
+		return prices[i] < prices[j]
+	})
+	if len(prices) > bookDepth {
+		for _, price := range prices[bookDepth:] {
+			delete(levels, keys[price])
+		}
+		prices = prices[:bookDepth]
+	}
+	return prices, nil
+}
+
+// parseTradeMsg parses the trades of a pair.
+//
+// API doc: https://docs.kraken.com/websockets/#message-trade
+//
+// For example:
+// [0,[["5541.20000","0.15850568","1534614057.321597","s","l",""]],"trade","XBT/USD"]
+func parseTradeMsg(rawMsg []byte) (*types.TradeMsg, error) {
+	var arr []json.RawMessage
+	if err := json.Unmarshal(rawMsg, &arr); err != nil {
+		return nil, fmt.Errorf("Invalid msg %s", string(rawMsg))
+	}
+	if len(arr) != 4 {
+		return nil, fmt.Errorf("Invalid msg %s", string(rawMsg))
+	}
+	var entries [][]interface{}
+	var name, symbol string
+	if err := json.Unmarshal(arr[1], &entries); err != nil {
+		return nil, fmt.Errorf("Invalid trades %s", string(rawMsg))
+	}
+	if err := json.Unmarshal(arr[2], &name); err != nil || name != "trade" {
+		return nil, fmt.Errorf("Not trade %s", string(rawMsg))
+	}
+	if err := json.Unmarshal(arr[3], &symbol); err != nil {
+		return nil, fmt.Errorf("Invalid msg %s", string(rawMsg))
+	}
+
+	base, quote, err := parser.ParseSymbol(exchangeName, symbol)
+	if err != nil {
+		return nil, err
+	}
+	var trades []types.Trade
+	for _, entry := range entries {
+		if len(entry) < 3 {
+			return nil, fmt.Errorf("Invalid trade %s", string(rawMsg))
+		}
+		price, err := strconv.ParseFloat(entry[0].(string), 64)
+		if err != nil {
+			return nil, err
+		}
+		volume, err := strconv.ParseFloat(entry[1].(string), 64)
+		if err != nil {
+			return nil, err
+		}
+		seconds, err := strconv.ParseFloat(entry[2].(string), 64)
+		if err != nil {
+			return nil, err
+		}
+		trades = append(trades, types.Trade{Timestamp: uint64(seconds * 1e3), Price: price, Volume: volume})
+	}
+
+	return &types.TradeMsg{
+		Exchange: exchangeName,
+		Symbol:   symbol,
+		Base:     base,
+		Quote:    quote,
+		Trades:   trades,
+	}, nil
+}
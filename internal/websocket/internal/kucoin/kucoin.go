@@ -6,6 +6,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -16,17 +17,24 @@ import (
 )
 
 const (
+	tokenUrl     string = "https://openapi-v2.kucoin.com/api/v1/bullet-public"
 	exchangeName string = "kucoin"
 )
 
-type WebsocketClient struct{}
+type WebsocketClient struct {
+	tokenUrl string
+}
 
 func NewWebsocketClient() *WebsocketClient {
-	return &WebsocketClient{}
+	url := tokenUrl
+	if envUrl := os.Getenv("KUCOIN_TOKEN_URL"); envUrl != "" {
+		url = envUrl
+	}
+	return &WebsocketClient{tokenUrl: url}
 }
 
 func (wc *WebsocketClient) ConnectAndSubscribe(symbols []string) (*websocket.Conn, error) {
-	wsToken, err := fetchWebsocketToken()
+	wsToken, err := fetchWebsocketToken(wc.tokenUrl)
 	if err != nil {
 		return nil, err
 	}
@@ -211,8 +219,7 @@ type websocketToken struct {
 }
 
 // see https://docs.kucoin.com/#apply-connect-token
-func fetchWebsocketToken() (*websocketToken, error) {
-	url := "https://openapi-v2.kucoin.com/api/v1/bullet-public"
+func fetchWebsocketToken(url string) (*websocketToken, error) {
 	client := &http.Client{Timeout: time.Second * 15}
 	request, err := http.NewRequest("POST", url, nil)
 	if err != nil {
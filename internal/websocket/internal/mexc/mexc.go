@@ -0,0 +1,192 @@
+package mexc
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/terra-money/oracle-feeder-go/internal/parser"
+	"github.com/terra-money/oracle-feeder-go/internal/types"
+)
+
+const (
+	// API doc: https://mexcdevelop.github.io/apidocs/spot_v3_en/#websocket-market-streams
+	websocketUrl string = "wss://wbs.mexc.com/ws"
+	exchangeName string = "mexc"
+	klineChannel string = "spot@public.kline.v3.api"
+)
+
+type WebsocketClient struct {
+	url string
+}
+
+func NewWebsocketClient() *WebsocketClient {
+	url := websocketUrl
+	if envUrl := os.Getenv("MEXC_WEBSOCKET_URL"); envUrl != "" {
+		url = envUrl
+	}
+	return &WebsocketClient{url: url}
+}
+
+func (wc *WebsocketClient) ConnectAndSubscribe(symbols []string) (*websocket.Conn, error) {
+	conn, _, err := websocket.DefaultDialer.Dial(wc.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	command, err := generateCommand(symbols)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.WriteJSON(&command); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// send ping per 20 seconds until the connection is closed, e.g. on reconnect,
+	// the server closes connections without activity for 60 seconds
+	// see https://mexcdevelop.github.io/apidocs/spot_v3_en/#pingpong-mechanism
+	go func() {
+		ticker := time.NewTicker(20 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := conn.WriteJSON(map[string]string{"method": "PING"}); err != nil {
+				log.Printf("%s ping stopped: %v", exchangeName, err)
+				return
+			}
+		}
+	}()
+
+	return conn, nil
+}
+
+func (wc *WebsocketClient) HandleMsg(msg []byte, conn *websocket.Conn) (*types.CandlestickMsg, error) {
+	resp := make(map[string]interface{})
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		return nil, err
+	}
+
+	// subscription and ping responses
+	// {"id":0,"code":0,"msg":"PONG"}
+	if code, ok := resp["code"].(float64); ok {
+		if code != 0 {
+			return nil, fmt.Errorf("msg error: %s", string(msg))
+		}
+		return nil, nil
+	}
+
+	channel, ok := resp["c"].(string)
+	if !ok || !strings.HasPrefix(channel, klineChannel) {
+		log.Printf("receive msg: %s\n", string(msg))
+		return nil, nil
+	}
+
+	return parseCandlestickMsg(msg)
+}
+
+// Candlestick websocket message.
+//
+// Message format: https://mexcdevelop.github.io/apidocs/spot_v3_en/#kline-streams
+type RawCandlestickMsg struct {
+	Channel string `json:"c"`
+	Symbol  string `json:"s"`
+	Time    uint64 `json:"t"`
+	Data    struct {
+		Kline struct {
+			Open        string `json:"o"`
+			Close       string `json:"c"`
+			High        string `json:"h"`
+			Low         string `json:"l"`
+			BaseVolume  string `json:"v"`
+			QuoteVolume string `json:"a"`
+		} `json:"k"`
+	} `json:"d"`
+}
+
+// generateCommand generates the candlestick subscription command from specified symbols.
+//
+// API doc: https://mexcdevelop.github.io/apidocs/spot_v3_en/#kline-streams
+//
+// MEXC allows 30 subscriptions per connection.
+//
+// For example:
+// {"method":"SUBSCRIPTION","params":["spot@public.kline.v3.api@BTCUSDT@Min1"]}
+func generateCommand(symbols []string) (map[string]interface{}, error) {
+	if len(symbols) > 30 {
+		return nil, fmt.Errorf("exceeds 30 symbols")
+	}
+	var params []string
+	for _, symbol := range symbols {
+		params = append(params, fmt.Sprintf("%s@%s@Min1", klineChannel, strings.ToUpper(symbol)))
+	}
+	return map[string]interface{}{
+		"method": "SUBSCRIPTION",
+		"params": params,
+	}, nil
+}
+
+func parseCandlestickMsg(rawMsg []byte) (*types.CandlestickMsg, error) {
+	var msg RawCandlestickMsg
+	err := json.Unmarshal(rawMsg, &msg)
+	if err != nil {
+		return nil, err
+	}
+
+	symbol := msg.Symbol
+	base, quote, err := parser.ParseSymbol(exchangeName, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	kline := msg.Data.Kline
+	open, err := strconv.ParseFloat(kline.Open, 64)
+	if err != nil {
+		return nil, err
+	}
+	high, err := strconv.ParseFloat(kline.High, 64)
+	if err != nil {
+		return nil, err
+	}
+	low, err := strconv.ParseFloat(kline.Low, 64)
+	if err != nil {
+		return nil, err
+	}
+	close, err := strconv.ParseFloat(kline.Close, 64)
+	if err != nil {
+		return nil, err
+	}
+	baseVolume, err := strconv.ParseFloat(kline.BaseVolume, 64)
+	if err != nil {
+		return nil, err
+	}
+	quoteVolume, err := strconv.ParseFloat(kline.QuoteVolume, 64)
+	if err != nil {
+		return nil, err
+	}
+	vwap := 0.0
+	if baseVolume == 0.0 || quoteVolume == 0.0 {
+		vwap = (open + close) / 2.0
+	} else {
+		vwap = quoteVolume / baseVolume
+	}
+
+	return &types.CandlestickMsg{
+		Exchange:  exchangeName,
+		Symbol:    symbol,
+		Base:      base,
+		Quote:     quote,
+		Timestamp: msg.Time,
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     close,
+		Volume:    baseVolume,
+		Vwap:      vwap,
+	}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"os"
 	"strconv"
 	"time"
 
@@ -14,17 +15,42 @@ import (
 
 const (
 	websocketUrl string = "wss://ws.okx.com:8443/ws/v5/business"
-	exchangeName string = "okx"
+	// the tickers and trades channels are served by the public endpoint only
+	publicWebsocketUrl string = "wss://ws.okx.com:8443/ws/v5/public"
+	exchangeName       string = "okx"
 )
 
-type WebsocketClient struct{}
+type WebsocketClient struct {
+	url       string
+	publicUrl string
+}
 
 func NewWebsocketClient() *WebsocketClient {
-	return &WebsocketClient{}
+	url := websocketUrl
+	if envUrl := os.Getenv("OKX_WEBSOCKET_URL"); envUrl != "" {
+		url = envUrl
+	}
+	publicUrl := publicWebsocketUrl
+	if envUrl := os.Getenv("OKX_PUBLIC_WEBSOCKET_URL"); envUrl != "" {
+		publicUrl = envUrl
+	}
+	return &WebsocketClient{url: url, publicUrl: publicUrl}
 }
 
 func (wc *WebsocketClient) ConnectAndSubscribe(symbols []string) (*websocket.Conn, error) {
-	conn, _, err := websocket.DefaultDialer.Dial(websocketUrl, nil)
+	return connectAndSubscribe(wc.url, generateCommand(symbols, "candle1m"))
+}
+
+func (wc *WebsocketClient) ConnectAndSubscribeBookTicker(symbols []string) (*websocket.Conn, error) {
+	return connectAndSubscribe(wc.publicUrl, generateCommand(symbols, "tickers"))
+}
+
+func (wc *WebsocketClient) ConnectAndSubscribeTrades(symbols []string) (*websocket.Conn, error) {
+	return connectAndSubscribe(wc.publicUrl, generateCommand(symbols, "trades"))
+}
+
+func connectAndSubscribe(url string, command map[string]interface{}) (*websocket.Conn, error) {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -41,7 +67,6 @@ func (wc *WebsocketClient) ConnectAndSubscribe(symbols []string) (*websocket.Con
 		}
 	}()
 
-	command, err := generateCommand(symbols)
 	if err := conn.WriteJSON(&command); err != nil {
 		return nil, err
 	}
@@ -50,42 +75,63 @@ func (wc *WebsocketClient) ConnectAndSubscribe(symbols []string) (*websocket.Con
 }
 
 func (wc *WebsocketClient) HandleMsg(msg []byte, conn *websocket.Conn) (*types.CandlestickMsg, error) {
+	isData, err := isDataMsg(msg)
+	if !isData {
+		return nil, err
+	}
+	return parseCandlestickMsg(msg)
+}
+
+func (wc *WebsocketClient) HandleBookTickerMsg(msg []byte, conn *websocket.Conn) (*types.BookTickerMsg, error) {
+	isData, err := isDataMsg(msg)
+	if !isData {
+		return nil, err
+	}
+	return parseBookTickerMsg(msg)
+}
+
+func (wc *WebsocketClient) HandleTradeMsg(msg []byte, conn *websocket.Conn) (*types.TradeMsg, error) {
+	isData, err := isDataMsg(msg)
+	if !isData {
+		return nil, err
+	}
+	return parseTradeMsg(msg)
+}
+
+// isDataMsg tells apart the channel data from pongs and events, failing on error events.
+func isDataMsg(msg []byte) (bool, error) {
 	if string(msg) == "pong" {
-		return nil, nil
+		return false, nil
 	}
 	resp := make(map[string]interface{})
 	if err := json.Unmarshal(msg, &resp); err != nil {
-		return nil, err
+		return false, err
 	}
 
 	if event, ok := resp["event"].(string); ok {
 		if event == "error" {
-			return nil, fmt.Errorf("%s", string(msg))
+			return false, fmt.Errorf("%s", string(msg))
 		} else {
-			return nil, nil
+			return false, nil
 		}
 	}
 
 	_, argExist := resp["arg"].(map[string]interface{})
 	_, dataExist := resp["data"].([]interface{})
-	if argExist && dataExist {
-		return parseCandlestickMsg(msg)
-	} else {
-		return nil, nil
-	}
+	return argExist && dataExist, nil
 }
 
-// generateCommand generates the candlestick subscription command from specified symbols.
+// generateCommand generates the subscription command to the given channel of specified symbols.
 //
 // API doc: https://www.okx.com/docs-v5/en/#websocket-api-public-channel-candlesticks-channel
 //
 // For example:
 // {"op":"subscribe","args":[{"channel":"candle1m","instId":"BTC-USDT"},{"channel":"candle1m","instId":"ETH-USDT"}]}
-func generateCommand(symbols []string) (map[string]interface{}, error) {
+func generateCommand(symbols []string, channel string) map[string]interface{} {
 	var args []map[string]string
 	for _, symbol := range symbols {
 		arg := map[string]string{
-			"channel": "candle1m",
+			"channel": channel,
 			"instId":  symbol,
 		}
 		args = append(args, arg)
@@ -93,7 +139,7 @@ func generateCommand(symbols []string) (map[string]interface{}, error) {
 	return map[string]interface{}{
 		"op":   "subscribe",
 		"args": args,
-	}, nil
+	}
 }
 
 // https://docs.kraken.com/websockets/#message-ohlc
@@ -171,3 +217,94 @@ func parseCandlestickMsg(rawMsg []byte) (*types.CandlestickMsg, error) {
 		Vwap:      vwap,
 	}, nil
 }
+
+// https://www.okx.com/docs-v5/en/#websocket-api-public-channel-tickers-channel
+func parseBookTickerMsg(rawMsg []byte) (*types.BookTickerMsg, error) {
+	var resp struct {
+		Arg struct {
+			Channel string `json:"channel"`
+		} `json:"arg"`
+		Data []struct {
+			InstId string `json:"instId"`
+			BidPx  string `json:"bidPx"`
+			AskPx  string `json:"askPx"`
+			Ts     string `json:"ts"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rawMsg, &resp); err != nil {
+		return nil, fmt.Errorf("invalid ticker %s", string(rawMsg))
+	}
+	if resp.Arg.Channel != "tickers" || len(resp.Data) != 1 {
+		return nil, fmt.Errorf("invalid ticker %s", string(rawMsg))
+	}
+	ticker := resp.Data[0]
+
+	base, quote, err := parser.ParseSymbol(exchangeName, ticker.InstId)
+	if err != nil {
+		return nil, err
+	}
+	timestamp, err := strconv.ParseUint(ticker.Ts, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	bid, err := strconv.ParseFloat(ticker.BidPx, 64)
+	if err != nil {
+		return nil, err
+	}
+	ask, err := strconv.ParseFloat(ticker.AskPx, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewBookTickerMsg(exchangeName, ticker.InstId, base, quote, timestamp, bid, ask), nil
+}
+
+// https://www.okx.com/docs-v5/en/#websocket-api-public-channel-trades-channel
+func parseTradeMsg(rawMsg []byte) (*types.TradeMsg, error) {
+	var resp struct {
+		Arg struct {
+			Channel string `json:"channel"`
+			InstId  string `json:"instId"`
+		} `json:"arg"`
+		Data []struct {
+			Px string `json:"px"`
+			Sz string `json:"sz"`
+			Ts string `json:"ts"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rawMsg, &resp); err != nil {
+		return nil, fmt.Errorf("invalid trades %s", string(rawMsg))
+	}
+	if resp.Arg.Channel != "trades" {
+		return nil, fmt.Errorf("invalid trades %s", string(rawMsg))
+	}
+
+	base, quote, err := parser.ParseSymbol(exchangeName, resp.Arg.InstId)
+	if err != nil {
+		return nil, err
+	}
+	var trades []types.Trade
+	for _, data := range resp.Data {
+		timestamp, err := strconv.ParseUint(data.Ts, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		price, err := strconv.ParseFloat(data.Px, 64)
+		if err != nil {
+			return nil, err
+		}
+		volume, err := strconv.ParseFloat(data.Sz, 64)
+		if err != nil {
+			return nil, err
+		}
+		trades = append(trades, types.Trade{Timestamp: timestamp, Price: price, Volume: volume})
+	}
+
+	return &types.TradeMsg{
+		Exchange: exchangeName,
+		Symbol:   resp.Arg.InstId,
+		Base:     base,
+		Quote:    quote,
+		Trades:   trades,
+	}, nil
+}
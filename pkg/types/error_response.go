@@ -0,0 +1,25 @@
+package types
+
+import "fmt"
+
+// ErrorResponse represents the JSON response of a failed request to the /v1 API.
+type ErrorResponse struct {
+	Error APIError `json:"error"`
+}
+
+// APIError describes why a request failed. Upstream is the node URL,
+// contract address or LSD symbol that failed, and Retryable tells whether
+// the same request may succeed later.
+type APIError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Upstream  string `json:"upstream,omitempty"`
+	Retryable bool   `json:"retryable"`
+}
+
+func (e APIError) Error() string {
+	if e.Upstream != "" {
+		return fmt.Sprintf("%s: %s (upstream %s)", e.Code, e.Message, e.Upstream)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
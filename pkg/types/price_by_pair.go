@@ -2,8 +2,11 @@ package types
 
 // PriceByPair represents the USD price of a coin at a timestamp.
 type PriceByPair struct {
-	Base      string // Unified coin name, e.g., XBT is converted to BTC
-	Quote     string // Unified coin name, e.g., XBT is converted to BTC
-	Price     float64
-	Timestamp uint64
+	Base        string  `json:"base"`  // Unified coin name, e.g., XBT is converted to BTC
+	Quote       string  `json:"quote"` // Unified coin name, e.g., XBT is converted to BTC
+	Price       float64 `json:"price"`
+	Timestamp   uint64  `json:"timestamp"`
+	PriceImpact float64 `json:"price_impact,omitempty"` // price impact of the simulated swap, DEX providers only
+	Spread      float64 `json:"spread,omitempty"`       // relative bid-ask spread, order-book mode of websocket providers only
+	Stale       bool    `json:"stale,omitempty"`        // last good price served again because fetching a fresh one failed
 }
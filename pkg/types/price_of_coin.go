@@ -5,4 +5,6 @@ type PriceOfCoin struct {
 	Denom     string  `json:"denom"` // Unified denom name, e.g., XBT is converted to BTC
 	Price     float64 `json:"price"`
 	Timestamp uint64  `json:"-"`
+	Deviation float64 `json:"deviation,omitempty"` // relative deviation from the reference oracle, when it prices the denom
+	Divergent bool    `json:"divergent,omitempty"` // the deviation exceeds the maximum allowed by the config
 }
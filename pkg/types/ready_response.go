@@ -0,0 +1,15 @@
+package types
+
+// ReadyResponse represents the JSON response of GET /ready.
+type ReadyResponse struct {
+	Ready  bool         `json:"ready"`
+	Checks []ReadyCheck `json:"checks"`
+}
+
+// ReadyCheck is one of the conditions of the readiness, e.g. the
+// freshness of a denom, with the detail of what is failing.
+type ReadyCheck struct {
+	Name   string `json:"name"` // e.g. providers, denom:LUNA or alliance:protocol
+	Ok     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
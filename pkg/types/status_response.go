@@ -0,0 +1,7 @@
+package types
+
+// StatusResponse represents the JSON response of the prices held by each provider.
+type StatusResponse struct {
+	Timestamp string                            `json:"created_at"` // RFC3339
+	Providers map[string]map[string]PriceByPair `json:"providers"`  // exchange -> pair -> price
+}